@@ -0,0 +1,209 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditEventType is the kind of change recorded in the audit table
+type AuditEventType string
+
+const (
+	AuditEventAcquire        AuditEventType = "acquire"         // A lock was acquired
+	AuditEventRefresh        AuditEventType = "refresh"         // A held lock was extended
+	AuditEventRelease        AuditEventType = "release"         // A lock was released by its holder
+	AuditEventExpireTakeover AuditEventType = "expire_takeover" // An expired lock was cleared by another acquirer
+	AuditEventForceUnlock    AuditEventType = "force_unlock"    // A lock was released by ForceUnlock
+)
+
+// LockAuditEvent is a row of the audit table
+type LockAuditEvent struct {
+	ID         int64           // Sequence number of the event
+	Name       string          // Lock Name
+	LockID     string          // LockID of the holder the event is about
+	Mode       LockMode        // Mode of the lock the event is about
+	Event      AuditEventType  // Kind of change
+	OccurredAt time.Time       // When the change was committed
+	ExpiresAt  time.Time       // Expiration time of the lock after (or, for expire_takeover, before) the change
+	Metadata   json.RawMessage // Metadata passed by the caller, if any
+}
+
+func (c *lockClient) createLockAuditTable(ctx context.Context) error {
+	tableName := c.options.LockAuditTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			name TEXT NOT NULL,
+			lock_id TEXT NOT NULL,
+			mode TEXT NOT NULL,
+			event TEXT NOT NULL,
+			occurred_at TIMESTAMPTZ NOT NULL,
+			expires_at TIMESTAMPTZ,
+			metadata JSONB
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+	if err != nil {
+		return err
+	}
+
+	createIndexSQL := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_name_occurred_at ON %s (name, occurred_at);
+	`, tableName, tableName)
+
+	_, err = c.db.ExecContext(ctx, createIndexSQL)
+
+	return err
+}
+
+// recordAuditEvents writes the events in the caller's transaction. It does nothing unless audit is enabled.
+func (c *lockClient) recordAuditEvents(ctx context.Context, tx *sql.Tx, events ...LockAuditEvent) error {
+	if !c.options.EnableAudit || len(events) == 0 {
+		return nil
+	}
+
+	tableName := c.options.LockAuditTableName
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, lock_id, mode, event, occurred_at, expires_at, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`, tableName)
+
+	for _, event := range events {
+		expiresAt := sql.NullTime{Time: event.ExpiresAt, Valid: !event.ExpiresAt.IsZero()}
+
+		var metadata any
+		if hasJSONValue(event.Metadata) {
+			metadata = []byte(event.Metadata)
+		}
+
+		_, err := tx.ExecContext(
+			ctx, insertQuery,
+			event.Name, event.LockID, string(event.Mode), string(event.Event), event.OccurredAt, expiresAt, metadata,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record audit event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type GetLockHistoryParams struct {
+	Name  string    // Lock Name: unique identifier for the lock
+	From  time.Time // Only events at or after this time (zero for no lower bound)
+	To    time.Time // Only events before this time (zero for no upper bound)
+	Limit int       // Maximum number of events to return (default value: 100)
+}
+
+type GetLockHistoryResult struct {
+	Events []LockAuditEvent // Events in the order they occurred
+}
+
+// GetLockHistory returns the audit events of a lock in a time range.
+func (c *lockClient) GetLockHistory(ctx context.Context, params GetLockHistoryParams) (GetLockHistoryResult, error) {
+	if !c.options.EnableAudit {
+		return GetLockHistoryResult{}, ErrAuditDisabled
+	}
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	tableName := c.options.LockAuditTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT id, name, lock_id, mode, event, occurred_at, expires_at, metadata
+		FROM %s
+		WHERE name = $1
+			AND ($2::timestamptz IS NULL OR occurred_at >= $2)
+			AND ($3::timestamptz IS NULL OR occurred_at < $3)
+		ORDER BY occurred_at ASC, id ASC
+		LIMIT $4;
+	`, tableName)
+
+	from := sql.NullTime{Time: params.From, Valid: !params.From.IsZero()}
+	to := sql.NullTime{Time: params.To, Valid: !params.To.IsZero()}
+
+	rows, err := c.db.QueryContext(ctx, selectQuery, params.Name, from, to, params.Limit)
+	if err != nil {
+		return GetLockHistoryResult{}, err
+	}
+	defer rows.Close()
+
+	events := []LockAuditEvent{}
+	for rows.Next() {
+		var event LockAuditEvent
+		var mode string
+		var eventType string
+		var expiresAt sql.NullTime
+		var metadata []byte
+
+		err := rows.Scan(
+			&event.ID, &event.Name, &event.LockID, &mode, &eventType, &event.OccurredAt, &expiresAt, &metadata,
+		)
+		if err != nil {
+			return GetLockHistoryResult{}, err
+		}
+
+		event.Mode = LockMode(mode)
+		event.Event = AuditEventType(eventType)
+		if expiresAt.Valid {
+			event.ExpiresAt = expiresAt.Time
+		}
+		if len(metadata) > 0 {
+			event.Metadata = json.RawMessage(metadata)
+		}
+
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return GetLockHistoryResult{}, err
+	}
+
+	return GetLockHistoryResult{Events: events}, nil
+}
+
+type PruneLockHistoryParams struct {
+	Retention time.Duration // Events older than now - Retention are deleted
+}
+
+type PruneLockHistoryResult struct {
+	Deleted int64 // Number of deleted events
+}
+
+// PruneLockHistory deletes audit events older than the retention period.
+func (c *lockClient) PruneLockHistory(ctx context.Context, params PruneLockHistoryParams) (PruneLockHistoryResult, error) {
+	if !c.options.EnableAudit {
+		return PruneLockHistoryResult{}, ErrAuditDisabled
+	}
+
+	tableName := c.options.LockAuditTableName
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE occurred_at < $1;
+	`, tableName)
+
+	result, err := c.db.ExecContext(ctx, deleteQuery, time.Now().Add(-params.Retention))
+	if err != nil {
+		return PruneLockHistoryResult{}, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return PruneLockHistoryResult{}, err
+	}
+
+	return PruneLockHistoryResult{Deleted: deleted}, nil
+}
+
+// hasJSONValue reports whether raw holds a JSON value other than null.
+// A nil RawMessage round-tripped through encoding/json comes back as "null".
+func hasJSONValue(raw json.RawMessage) bool {
+	return len(raw) > 0 && string(raw) != "null"
+}
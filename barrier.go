@@ -0,0 +1,331 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A barrier makes a fixed number of participants wait for each other: every participant
+// arrives and waits until Parties participants have arrived, then all of them are released
+// together and the barrier starts a new generation for the next round.
+// Participants that stop polling expire after their TTL and no longer count as arrived.
+
+const (
+	// DefaultBarrierTTLSeconds is the default TTL of a participant waiting at a Barrier
+	DefaultBarrierTTLSeconds = 30
+)
+
+// errInvalidBarrierParties is returned when a barrier is entered with fewer than one participant
+var errInvalidBarrierParties = errors.New("pglock: barrier parties must be positive")
+
+func (c *lockClient) createBarrierTable(ctx context.Context) error {
+	tableName := c.options.BarrierTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			name TEXT PRIMARY KEY,
+			generation BIGINT NOT NULL,
+			parties INT NOT NULL,
+			participants JSONB NOT NULL DEFAULT '[]'::jsonb
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+
+	return err
+}
+
+// BarrierParticipant is a participant waiting at a barrier
+type BarrierParticipant struct {
+	LockID    string    `json:"lock_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ArriveBarrierParams struct {
+	Name       string // Barrier Name
+	LockID     string // Identifier of the participant
+	Parties    int    // Number of participants that must arrive before the barrier opens
+	TTLSeconds int    // Time-To-Live: the participant stops counting as arrived unless it calls again within this duration
+	Generation int64  // Generation returned by the previous call while waiting (0 to join the current generation)
+}
+
+type ArriveBarrierResult struct {
+	Generation int64 // Generation the participant arrived in
+	Arrived    int   // Number of live participants of the generation so far (0 once released)
+	Released   bool  // Whether the generation has been released
+}
+
+type LeaveBarrierParams struct {
+	Name       string // Barrier Name
+	LockID     string // Identifier of the participant
+	Generation int64  // Generation the participant arrived in
+}
+
+type LeaveBarrierResult struct {
+	Left bool // Whether the participant was still waiting in the generation
+}
+
+// arriveBarrier applies an arrival to the state of a barrier and reports the outcome.
+// It returns the participants and generation to store.
+func arriveBarrier(
+	generation int64,
+	participants []BarrierParticipant,
+	params ArriveBarrierParams,
+	now time.Time,
+) (int64, []BarrierParticipant, ArriveBarrierResult) {
+	// 기다리던 세대가 이미 열렸으면 통과
+	if params.Generation != 0 && params.Generation < generation {
+		return generation, participants, ArriveBarrierResult{Generation: params.Generation, Released: true}
+	}
+
+	// 만료된 참가자 정리 후 자신을 추가 (이미 있으면 TTL 갱신)
+	live := []BarrierParticipant{}
+	for _, participant := range participants {
+		if participant.LockID == params.LockID || !participant.ExpiresAt.After(now) {
+			continue
+		}
+		live = append(live, participant)
+	}
+	live = append(live, BarrierParticipant{
+		LockID:    params.LockID,
+		ExpiresAt: now.Add(time.Duration(params.TTLSeconds) * time.Second),
+	})
+
+	// 모두 도착하면 다음 세대로 넘어가며 전원 통과
+	if len(live) >= params.Parties {
+		return generation + 1, []BarrierParticipant{}, ArriveBarrierResult{Generation: generation, Released: true}
+	}
+
+	return generation, live, ArriveBarrierResult{Generation: generation, Arrived: len(live)}
+}
+
+// leaveBarrier removes a participant from the generation it is waiting in.
+func leaveBarrier(
+	generation int64,
+	participants []BarrierParticipant,
+	params LeaveBarrierParams,
+	now time.Time,
+) ([]BarrierParticipant, bool) {
+	if params.Generation != generation {
+		return participants, false
+	}
+
+	left := false
+	remaining := []BarrierParticipant{}
+	for _, participant := range participants {
+		if participant.LockID == params.LockID {
+			left = participant.ExpiresAt.After(now)
+			continue
+		}
+		remaining = append(remaining, participant)
+	}
+
+	return remaining, left
+}
+
+// ArriveBarrier registers the participant at the barrier, or checks on it while waiting.
+// The barrier opens once Parties live participants have arrived; call again with the returned
+// Generation until Released (which also renews the participant's TTL).
+func (c *lockClient) ArriveBarrier(ctx context.Context, params ArriveBarrierParams) (ArriveBarrierResult, error) {
+	if params.Parties <= 0 {
+		return ArriveBarrierResult{}, errInvalidBarrierParties
+	}
+
+	transaction, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ArriveBarrierResult{}, err
+	}
+	defer transaction.Rollback()
+
+	tableName := c.options.BarrierTableName
+
+	// 1. barrier 행 생성 (없으면) 후 행 잠금
+	ensureQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, generation, parties, participants)
+		VALUES ($1, 1, $2, '[]'::jsonb)
+		ON CONFLICT (name) DO NOTHING;
+	`, tableName)
+	if _, err := transaction.ExecContext(ctx, ensureQuery, params.Name, params.Parties); err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	generation, participants, err := c.selectBarrierForUpdate(ctx, transaction, params.Name)
+	if err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	// 2. 도착 반영
+	generation, participants, result := arriveBarrier(generation, participants, params, time.Now())
+
+	participantsJSON, err := json.Marshal(participants)
+	if err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET generation = $1, parties = $2, participants = $3
+		WHERE name = $4;
+	`, tableName)
+	if _, err := transaction.ExecContext(ctx, updateQuery, generation, params.Parties, participantsJSON, params.Name); err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	return result, nil
+}
+
+// LeaveBarrier withdraws a participant that gave up waiting, so that it no longer counts as arrived.
+func (c *lockClient) LeaveBarrier(ctx context.Context, params LeaveBarrierParams) (LeaveBarrierResult, error) {
+	transaction, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return LeaveBarrierResult{}, err
+	}
+	defer transaction.Rollback()
+
+	generation, participants, err := c.selectBarrierForUpdate(ctx, transaction, params.Name)
+	if err == sql.ErrNoRows {
+		return LeaveBarrierResult{Left: false}, nil
+	}
+	if err != nil {
+		return LeaveBarrierResult{}, err
+	}
+
+	participants, left := leaveBarrier(generation, participants, params, time.Now())
+
+	participantsJSON, err := json.Marshal(participants)
+	if err != nil {
+		return LeaveBarrierResult{}, err
+	}
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET participants = $1
+		WHERE name = $2;
+	`, c.options.BarrierTableName)
+	if _, err := transaction.ExecContext(ctx, updateQuery, participantsJSON, params.Name); err != nil {
+		return LeaveBarrierResult{}, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return LeaveBarrierResult{}, err
+	}
+
+	return LeaveBarrierResult{Left: left}, nil
+}
+
+// selectBarrierForUpdate locks the row of a barrier and returns its generation and participants.
+func (c *lockClient) selectBarrierForUpdate(ctx context.Context, transaction *sql.Tx, name string) (int64, []BarrierParticipant, error) {
+	selectQuery := fmt.Sprintf(`
+		SELECT generation, participants
+		FROM %s
+		WHERE name = $1
+		FOR UPDATE;
+	`, c.options.BarrierTableName)
+
+	var generation int64
+	var participantsJSON []byte
+	if err := transaction.QueryRowContext(ctx, selectQuery, name).Scan(&generation, &participantsJSON); err != nil {
+		return 0, nil, err
+	}
+
+	participants := []BarrierParticipant{}
+	if err := json.Unmarshal(participantsJSON, &participants); err != nil {
+		return 0, nil, err
+	}
+
+	return generation, participants, nil
+}
+
+type BarrierOptions struct {
+	Name         string        // [required] Barrier name shared by the participants
+	Parties      int           // [required] Number of participants that must arrive before any of them proceeds
+	LockID       string        // [optional] Identifier of this participant. default: "<hostname>-<pid>-<sequence>", unique per barrier
+	TTLSeconds   int           // [optional] TTL of this participant while waiting, renewed at every poll. default: 30
+	PollInterval time.Duration // [optional] Interval between checks while waiting. default: 100ms
+}
+
+func (options *BarrierOptions) SetDefaults() {
+	if options.LockID == "" {
+		options.LockID = newProcessLockID()
+	}
+	if options.TTLSeconds <= 0 {
+		options.TTLSeconds = DefaultBarrierTTLSeconds
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultRetryInterval
+	}
+}
+
+// Barrier blocks participants in different processes until all of them have arrived.
+// Each Barrier is a single participant, so goroutines waiting at the same barrier need one each.
+// It can be entered again after it opens; every round is a new generation.
+type Barrier struct {
+	client  LockClient
+	options BarrierOptions
+}
+
+// NewBarrier returns a Barrier that coordinates through client, which must implement BarrierStore.
+func NewBarrier(client LockClient, options BarrierOptions) *Barrier {
+	options.SetDefaults()
+
+	return &Barrier{client: client, options: options}
+}
+
+// Enter arrives at the barrier and blocks until Parties participants have arrived or ctx is done.
+// If ctx is done first, the participant leaves the barrier and ctx's error is returned.
+func (b *Barrier) Enter(ctx context.Context) error {
+	store, err := storeOf[BarrierStore](b.client)
+	if err != nil {
+		return err
+	}
+
+	var generation int64
+
+	for {
+		result, err := store.ArriveBarrier(ctx, ArriveBarrierParams{
+			Name:       b.options.Name,
+			LockID:     b.options.LockID,
+			Parties:    b.options.Parties,
+			TTLSeconds: b.options.TTLSeconds,
+			Generation: generation,
+		})
+		if err != nil {
+			b.leave(ctx, store, generation)
+			return err
+		}
+		if result.Released {
+			return nil
+		}
+		generation = result.Generation
+
+		select {
+		case <-ctx.Done():
+			b.leave(ctx, store, generation)
+			return ctx.Err()
+		case <-time.After(b.options.PollInterval):
+			// 재확인
+		}
+	}
+}
+
+// leave withdraws from the generation after Enter gave up, so that the others do not count this participant.
+func (b *Barrier) leave(ctx context.Context, store BarrierStore, generation int64) {
+	if generation == 0 {
+		return
+	}
+
+	// ctx가 이미 끝났을 수 있으므로 취소되지 않는 컨텍스트로 정리 (실패하면 TTL 후 만료)
+	_, _ = store.LeaveBarrier(context.WithoutCancel(ctx), LeaveBarrierParams{
+		Name:       b.options.Name,
+		LockID:     b.options.LockID,
+		Generation: generation,
+	})
+}
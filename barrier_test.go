@@ -0,0 +1,99 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testArriveBarrier(t *testing.T, barriers BarrierStore, name string, lockID string, ttlSeconds int, generation int64) ArriveBarrierResult {
+	t.Helper()
+
+	result, err := barriers.ArriveBarrier(context.Background(), ArriveBarrierParams{
+		Name:       name,
+		LockID:     lockID,
+		Parties:    3,
+		TTLSeconds: ttlSeconds,
+		Generation: generation,
+	})
+	require.NoError(t, err)
+
+	return result
+}
+
+// TestBarrier_Generations tests that a barrier opens once every participant arrives and is then reused for the next generation
+func TestBarrier_Generations(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	barriers := client.(BarrierStore)
+	name := testLockName(t)
+
+	// 1. 3명 중 2명만 도착하면 대기, 같은 참가자의 재확인은 중복 집계되지 않음
+	first := testArriveBarrier(t, barriers, name, "worker_1", 60, 0)
+	assert.False(t, first.Released)
+	assert.Equal(t, 1, first.Arrived)
+
+	second := testArriveBarrier(t, barriers, name, "worker_2", 60, 0)
+	assert.False(t, second.Released)
+	assert.Equal(t, 2, second.Arrived)
+	assert.Equal(t, first.Generation, second.Generation)
+
+	assert.False(t, testArriveBarrier(t, barriers, name, "worker_1", 60, first.Generation).Released)
+
+	// 2. 마지막 참가자가 도착하면 열리고, 대기 중이던 참가자도 통과
+	third := testArriveBarrier(t, barriers, name, "worker_3", 60, 0)
+	assert.True(t, third.Released)
+	assert.Equal(t, first.Generation, third.Generation)
+
+	assert.True(t, testArriveBarrier(t, barriers, name, "worker_1", 60, first.Generation).Released)
+	assert.True(t, testArriveBarrier(t, barriers, name, "worker_2", 60, second.Generation).Released)
+
+	// 3. 열린 뒤에는 다음 세대로 재사용
+	next := testArriveBarrier(t, barriers, name, "worker_1", 1, 0)
+	assert.False(t, next.Released)
+	assert.Greater(t, next.Generation, first.Generation)
+	assert.Equal(t, 1, next.Arrived)
+
+	// 4. 만료된 참가자와 떠난 참가자는 도착 수에서 제외
+	backend.expire(1)
+
+	leaving := testArriveBarrier(t, barriers, name, "worker_2", 60, 0)
+	assert.Equal(t, 1, leaving.Arrived)
+
+	leaveResult, err := barriers.LeaveBarrier(ctx, LeaveBarrierParams{Name: name, LockID: "worker_2", Generation: leaving.Generation})
+	require.NoError(t, err)
+	assert.True(t, leaveResult.Left)
+
+	assert.Equal(t, 1, testArriveBarrier(t, barriers, name, "worker_3", 60, 0).Arrived)
+
+	// 5. Barrier.Enter는 모두 도착할 때까지 대기
+	enterName := testLockName(t) + "/enter"
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		barrier := NewBarrier(client, BarrierOptions{Name: enterName, Parties: 3, PollInterval: 10 * time.Millisecond})
+		go func() {
+			errs <- barrier.Enter(ctx)
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-errs:
+			require.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("barrier was not released")
+		}
+	}
+
+	// 도착하지 않은 참가자가 있으면 Enter는 ctx가 끝날 때 반환
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	barrier := NewBarrier(client, BarrierOptions{Name: enterName, Parties: 3, PollInterval: 10 * time.Millisecond})
+	assert.ErrorIs(t, barrier.Enter(timeoutCtx), context.DeadlineExceeded)
+
+	_, err = barriers.ArriveBarrier(ctx, ArriveBarrierParams{Name: enterName, LockID: "invalid", Parties: 0, TTLSeconds: 60})
+	assert.Error(t, err)
+}
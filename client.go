@@ -3,9 +3,11 @@ package pglock
 import (
 	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type LockClientOptions struct {
@@ -16,6 +18,26 @@ type LockClientOptions struct {
 	LockTableName              string // [optional] default: "lock"
 	PriorityLockTableName      string // [optional] default: "priority_lock"
 	PriorityLockQueueTableName string // [optional] default: "priority_lock_queue"
+	LockPolicyTableName        string // [optional] default: "lock_policy"
+	LockAuditTableName         string // [optional] default: "lock_audit"
+	LockSessionTableName       string // [optional] default: "lock_session"
+	HierarchyLockTableName     string // [optional] default: "hierarchy_lock"
+	BarrierTableName           string // [optional] default: "barrier"
+	LatchTableName             string // [optional] default: "latch"
+	TaskResultTableName        string // [optional] default: "task_result"
+	JobStatusTableName         string // [optional] default: "job_status"
+
+	Namespace string // [optional] prefix applied to every lock and policy name as "<namespace>:<name>", so that services sharing the tables cannot collide; must not contain ":" (Connect fails otherwise). default: none
+
+	EnableAudit bool // [optional] record lock events in LockAuditTableName, in the same transaction as the change. default: false
+
+	EnableTxLocks       bool  // [optional] enable TryXLockTx/XLockTx/TrySLockTx. Every acquisition then also takes an advisory lock and reads pg_locks. default: false
+	AdvisoryLockClassID int32 // [optional] first key of the advisory locks taken for transaction-scoped locks; keep it out of the application's own advisory keys. default: DefaultAdvisoryLockClassID
+
+	Observer Observer     // [optional] receives an event for every lock operation. default: none
+	Logger   *slog.Logger // [optional] debug-level events for attempts, conflicts, renewals and releases. default: discarded
+
+	TracerProvider trace.TracerProvider // [optional] creates spans around lock acquisition and release. default: no tracing
 }
 
 func (options *LockClientOptions) SetDefaults() {
@@ -28,6 +50,34 @@ func (options *LockClientOptions) SetDefaults() {
 	if options.PriorityLockQueueTableName == "" {
 		options.PriorityLockQueueTableName = "priority_lock_queue"
 	}
+	if options.LockPolicyTableName == "" {
+		options.LockPolicyTableName = "lock_policy"
+	}
+	if options.LockAuditTableName == "" {
+		options.LockAuditTableName = "lock_audit"
+	}
+	if options.LockSessionTableName == "" {
+		options.LockSessionTableName = "lock_session"
+	}
+	if options.HierarchyLockTableName == "" {
+		options.HierarchyLockTableName = "hierarchy_lock"
+	}
+	if options.BarrierTableName == "" {
+		options.BarrierTableName = "barrier"
+	}
+	if options.LatchTableName == "" {
+		options.LatchTableName = "latch"
+	}
+	if options.TaskResultTableName == "" {
+		options.TaskResultTableName = "task_result"
+	}
+	if options.JobStatusTableName == "" {
+		options.JobStatusTableName = "job_status"
+	}
+
+	if options.AdvisoryLockClassID == 0 {
+		options.AdvisoryLockClassID = DefaultAdvisoryLockClassID
+	}
 
 	if options.MaxIdleConnections == 0 {
 		options.MaxIdleConnections = 5
@@ -36,14 +86,30 @@ func (options *LockClientOptions) SetDefaults() {
 	if options.MaxOpenConnections == 0 {
 		options.MaxOpenConnections = 10
 	}
+
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
 }
 
 func NewLockClient(options LockClientOptions) LockClient {
 	options.SetDefaults()
 
-	return &lockClient{
+	client := &lockClient{
 		options: options,
+		instrumentation: instrumentation{
+			observer: options.Observer,
+			logger:   options.Logger,
+			tracer:   newTracer(options.TracerProvider),
+		},
+	}
+
+	// 잘못된 namespace는 Connect에서 에러로 반환
+	if validateNamespace(options.Namespace) != nil {
+		return client
 	}
+
+	return WithNamespace(client, options.Namespace)
 }
 
 type LockClient interface {
@@ -65,28 +131,158 @@ type LockClient interface {
 	// Acquire shared lock (blocking, waits until lock is available)
 	SLock(ctx context.Context, params SLockParams) (SLockResult, error)
 
+	// Try to acquire exclusive lock held until the caller's transaction commits or rolls back
+	TryXLockTx(ctx context.Context, tx *sql.Tx, params TryXLockTxParams) (TryXLockTxResult, error)
+	// Acquire exclusive lock held until the caller's transaction commits or rolls back (blocking)
+	XLockTx(ctx context.Context, tx *sql.Tx, params XLockTxParams) (XLockTxResult, error)
+	// Try to acquire shared lock held until the caller's transaction commits or rolls back
+	TrySLockTx(ctx context.Context, tx *sql.Tx, params TrySLockTxParams) (TrySLockTxResult, error)
+
 	// Release a lock (either exclusive or shared)
 	Unlock(ctx context.Context, params UnlockParams) (UnlockResult, error)
+	// Extend the TTL of a lock that is still held (either exclusive or shared)
+	Refresh(ctx context.Context, params RefreshParams) (RefreshResult, error)
+
+	// Get the value guarded by an exclusive lock, as its holder
+	GetValue(ctx context.Context, params GetValueParams) (GetValueResult, error)
+	// Replace the value guarded by an exclusive lock, as its holder
+	SetValue(ctx context.Context, params SetValueParams) (SetValueResult, error)
+
+	// Try to lock a node of a "/"-separated hierarchy, with intention locks on its ancestors (non-blocking)
+	TryHierarchyLock(ctx context.Context, params TryHierarchyLockParams) (TryHierarchyLockResult, error)
+	// Lock a node of a "/"-separated hierarchy, with intention locks on its ancestors (blocking)
+	HierarchyLock(ctx context.Context, params HierarchyLockParams) (HierarchyLockResult, error)
+	// Release a hierarchical lock and the intention locks taken for it
+	HierarchyUnlock(ctx context.Context, params HierarchyUnlockParams) (HierarchyUnlockResult, error)
+
+	// Register a session that locks can be bound to with SessionID
+	OpenSession(ctx context.Context, params OpenSessionParams) (OpenSessionResult, error)
+	// Extend the TTL of a session that is still alive (heartbeat)
+	RefreshSession(ctx context.Context, params RefreshSessionParams) (RefreshSessionResult, error)
+	// End a session, releasing every lock bound to it
+	CloseSession(ctx context.Context, params CloseSessionParams) (CloseSessionResult, error)
+
+	// Release every holder of a lock regardless of ownership
+	ForceUnlock(ctx context.Context, params ForceUnlockParams) (ForceUnlockResult, error)
+
+	// Get the current holders of a lock
+	DescribeLock(ctx context.Context, params DescribeLockParams) (DescribeLockResult, error)
+	// List locks in the lock table
+	ListLocks(ctx context.Context, params ListLocksParams) (ListLocksResult, error)
+
+	// Create or replace the policy for a lock name or prefix
+	SetLockPolicy(ctx context.Context, params SetLockPolicyParams) (SetLockPolicyResult, error)
+	// Get the policy effective for a lock name
+	GetLockPolicy(ctx context.Context, params GetLockPolicyParams) (GetLockPolicyResult, error)
+	// Delete the policy for a lock name or prefix
+	DeleteLockPolicy(ctx context.Context, params DeleteLockPolicyParams) (DeleteLockPolicyResult, error)
+
+	// List the namespaces of the lock table and their lock counts (never namespaced)
+	ListNamespaces(ctx context.Context, params ListNamespacesParams) (ListNamespacesResult, error)
+
+	// Get the audit events of a lock in a time range (requires EnableAudit)
+	GetLockHistory(ctx context.Context, params GetLockHistoryParams) (GetLockHistoryResult, error)
+	// Delete audit events older than the retention period (requires EnableAudit)
+	PruneLockHistory(ctx context.Context, params PruneLockHistoryParams) (PruneLockHistoryResult, error)
+}
+
+// The coordination helpers keep their state in tables of their own, behind the small interfaces below
+// rather than in LockClient. Every client in this module implements them; the helpers type-assert the
+// LockClient they are given and fail with ErrNotSupported if it does not.
+
+// BarrierStore backs Barrier.
+type BarrierStore interface {
+	// Arrive at a barrier, or check whether the generation waited in has been released (non-blocking)
+	ArriveBarrier(ctx context.Context, params ArriveBarrierParams) (ArriveBarrierResult, error)
+	// Withdraw a participant waiting at a barrier
+	LeaveBarrier(ctx context.Context, params LeaveBarrierParams) (LeaveBarrierResult, error)
+}
+
+// LatchStore backs CountDownLatch.
+type LatchStore interface {
+	// Create a countdown latch, unless it already exists
+	CreateLatch(ctx context.Context, params CreateLatchParams) (CreateLatchResult, error)
+	// Decrement the count of a countdown latch
+	CountDown(ctx context.Context, params CountDownParams) (CountDownResult, error)
+	// Get the state of a countdown latch
+	DescribeLatch(ctx context.Context, params DescribeLatchParams) (DescribeLatchResult, error)
+}
+
+// TaskResultStore backs RunOnce and Singleflight.
+type TaskResultStore interface {
+	// Record the outcome of a task, unless its key already has one
+	StoreTaskResult(ctx context.Context, params StoreTaskResultParams) (StoreTaskResultResult, error)
+	// Get the recorded outcome of a task
+	GetTaskResult(ctx context.Context, params GetTaskResultParams) (GetTaskResultResult, error)
+	// Discard the recorded outcome of a task
+	DeleteTaskResult(ctx context.Context, params DeleteTaskResultParams) (DeleteTaskResultResult, error)
+	// Delete the expired outcomes of every task (never namespaced)
+	PruneTaskResults(ctx context.Context, params PruneTaskResultsParams) (PruneTaskResultsResult, error)
+}
+
+// JobStatusStore backs the scheduler package.
+type JobStatusStore interface {
+	// Record the last run of a scheduled job
+	SaveJobStatus(ctx context.Context, params SaveJobStatusParams) (SaveJobStatusResult, error)
+	// Get the last run of a scheduled job
+	GetJobStatus(ctx context.Context, params GetJobStatusParams) (GetJobStatusResult, error)
+}
+
+var (
+	_ BarrierStore    = (*lockClient)(nil)
+	_ LatchStore      = (*lockClient)(nil)
+	_ TaskResultStore = (*lockClient)(nil)
+	_ JobStatusStore  = (*lockClient)(nil)
+)
+
+// storeOf returns client as the store interface S, or ErrNotSupported if it does not implement it.
+func storeOf[S any](client LockClient) (S, error) {
+	store, ok := client.(S)
+	if !ok {
+		return store, ErrNotSupported
+	}
+
+	return store, nil
 }
 
 type lockClient struct {
 	options LockClientOptions
 	db      *sql.DB
+
+	instrumentation
+}
+
+// instrumentation reports lock operations to the observer, logger and tracer.
+// It is shared by the LockClient implementations so that they report the same events.
+type instrumentation struct {
+	observer Observer
+	logger   *slog.Logger
+	tracer   trace.Tracer
 }
 
 func (c *lockClient) Connect() error {
+	if err := validateNamespace(c.options.Namespace); err != nil {
+		return err
+	}
+
 	if c.db != nil {
 		return nil
 	}
 
 	db, err := sql.Open("postgres", c.options.DatabaseURL)
 	if err != nil {
-		log.Fatalf("Failed to open database: %v", err)
+		return fmt.Errorf("failed to open database: %w", err)
 	}
 
 	db.SetMaxOpenConns(c.options.MaxOpenConnections)
 	db.SetMaxIdleConns(c.options.MaxIdleConnections)
 
+	// sql.Open은 연결을 만들지 않으므로 실제 연결 가능 여부 확인
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
 	c.db = db
 
 	return nil
@@ -97,6 +293,40 @@ func (c *lockClient) SetupTables() error {
 		return err
 	}
 
+	if err := c.createLockPolicyTable(context.Background()); err != nil {
+		return err
+	}
+
+	if err := c.createLockSessionTable(context.Background()); err != nil {
+		return err
+	}
+
+	if err := c.createHierarchyLockTable(context.Background()); err != nil {
+		return err
+	}
+
+	if err := c.createBarrierTable(context.Background()); err != nil {
+		return err
+	}
+
+	if err := c.createLatchTable(context.Background()); err != nil {
+		return err
+	}
+
+	if err := c.createTaskResultTable(context.Background()); err != nil {
+		return err
+	}
+
+	if err := c.createJobStatusTable(context.Background()); err != nil {
+		return err
+	}
+
+	if c.options.EnableAudit {
+		if err := c.createLockAuditTable(context.Background()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
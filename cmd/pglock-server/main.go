@@ -0,0 +1,101 @@
+// Command pglock-server serves a pglock lock client over HTTP/JSON so that services
+// without PostgreSQL credentials can use the locks through the remote package.
+//
+// The database URL is read from --dsn or the PGLOCK_DATABASE_URL environment variable,
+// and the bearer token from --token or PGLOCK_SERVER_TOKEN. The administrative methods
+// (SetupTables, ForceUnlock, lock policies, history pruning) are only served with
+// --admin-token or PGLOCK_SERVER_ADMIN_TOKEN.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/myyrakle/pglock"
+	"github.com/myyrakle/pglock/remote"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	dsn := flag.String("dsn", os.Getenv("PGLOCK_DATABASE_URL"), "PostgreSQL URL (default: $PGLOCK_DATABASE_URL)")
+	token := flag.String("token", os.Getenv("PGLOCK_SERVER_TOKEN"), "bearer token required from clients (default: $PGLOCK_SERVER_TOKEN)")
+	adminToken := flag.String("admin-token", os.Getenv("PGLOCK_SERVER_ADMIN_TOKEN"), "bearer token required for administrative methods, which are refused if unset (default: $PGLOCK_SERVER_ADMIN_TOKEN)")
+	prefix := flag.String("prefix", remote.DefaultPrefix, "path prefix of the API")
+	lockTable := flag.String("table", "", `lock table name (default: "lock")`)
+	enableAudit := flag.Bool("audit", false, "record lock events in the audit table")
+	maxOpenConnections := flag.Int("max-open-connections", 10, "maximum number of open database connections")
+	debug := flag.Bool("debug", false, "log lock events at debug level")
+	flag.Parse()
+
+	logLevel := slog.LevelInfo
+	if *debug {
+		logLevel = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+	if *dsn == "" {
+		logger.Error("no database URL, set --dsn or $PGLOCK_DATABASE_URL")
+		os.Exit(2)
+	}
+	if *token == "" {
+		logger.Error("no bearer token, set --token or $PGLOCK_SERVER_TOKEN")
+		os.Exit(2)
+	}
+	if *adminToken == "" {
+		logger.Info("no --admin-token set, administrative methods are refused")
+	}
+
+	lockClient := pglock.NewLockClient(pglock.LockClientOptions{
+		DatabaseURL:        *dsn,
+		MaxOpenConnections: *maxOpenConnections,
+		LockTableName:      *lockTable,
+		EnableAudit:        *enableAudit,
+		Logger:             logger,
+	})
+	if err := lockClient.Initialize(); err != nil {
+		logger.Error("failed to initialize lock client", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 종료 시 대기 중인 XLock/SLock 요청도 취소되도록 요청 컨텍스트의 부모로 사용
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	server := &http.Server{
+		Addr:        *addr,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+		Handler: remote.NewHandler(lockClient, remote.HandlerOptions{
+			Prefix:     *prefix,
+			Token:      *token,
+			AdminToken: *adminToken,
+			Logger:     logger,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		cancelRequests()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("pglock server listening", slog.String("addr", *addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("server failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
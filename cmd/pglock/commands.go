@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/myyrakle/pglock"
+)
+
+func runSetup(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("setup")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock setup [flags]\n\nCreates the lock tables if they do not exist.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+	if err := client.SetupTables(); err != nil {
+		return fail(err)
+	}
+
+	return printResult(global.format, map[string]any{"setup": true}, func() {
+		fmt.Println("tables are ready")
+	})
+}
+
+func runList(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("list")
+	prefix := fs.String("prefix", "", "only locks whose name starts with the prefix")
+	all := fs.Bool("all", false, "include locks without live holders")
+	limit := fs.Int("limit", 100, "maximum number of locks")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock list [flags]\n\nLists locks with live holders.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := client.ListLocks(ctx, pglock.ListLocksParams{
+		Prefix:      *prefix,
+		IncludeFree: *all,
+		Limit:       *limit,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	views := make([]lockView, 0, len(result.Locks))
+	for _, lock := range result.Locks {
+		views = append(views, newLockView(lock))
+	}
+
+	return printResult(global.format, views, func() {
+		printLockTable(views)
+	})
+}
+
+func runNamespaces(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("namespaces")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock namespaces [flags]\n\nLists the namespaces of the lock table with their lock counts.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := client.ListNamespaces(ctx, pglock.ListNamespacesParams{})
+	if err != nil {
+		return fail(err)
+	}
+
+	views := make([]namespaceView, 0, len(result.Namespaces))
+	for _, namespace := range result.Namespaces {
+		views = append(views, namespaceView(namespace))
+	}
+
+	return printResult(global.format, views, func() {
+		printNamespaces(views)
+	})
+}
+
+func runDescribe(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("describe")
+	name := fs.String("name", "", "lock name (required)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock describe --name NAME [flags]\n\nShows the live holders of a lock.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+	if !requireString("name", *name) {
+		return exitUsage
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := client.DescribeLock(ctx, pglock.DescribeLockParams{Name: *name})
+	if err != nil {
+		return fail(err)
+	}
+	if !result.Found {
+		result.Lock = pglock.LockInfo{Name: *name, MaxSharedLocks: -1}
+	}
+
+	view := newLockView(result.Lock)
+
+	return printResult(global.format, view, func() {
+		printLockDetail(view)
+	})
+}
+
+func runForceUnlock(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("force-unlock")
+	name := fs.String("name", "", "lock name (required)")
+	reason := fs.String("reason", "", "reason recorded in the audit history")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock force-unlock --name NAME [flags]\n\nReleases every holder of a lock, regardless of ownership.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+	if !requireString("name", *name) {
+		return exitUsage
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	metadata, err := json.Marshal(map[string]string{
+		"source": "pglock force-unlock",
+		"user":   currentUser(),
+		"reason": *reason,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := client.ForceUnlock(ctx, pglock.ForceUnlockParams{
+		Name:     *name,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	view := forceUnlockView{
+		Name:          *name,
+		Released:      result.Released,
+		XLockID:       result.XLockID,
+		SharedLockIDs: result.SharedLockIDs,
+	}
+
+	return printResult(global.format, view, func() {
+		printForceUnlock(view)
+	})
+}
+
+func runAcquire(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("acquire")
+	name := fs.String("name", "", "lock name (required)")
+	lockID := fs.String("lock-id", defaultLockID(), "lock id of the holder, needed to release the lock later")
+	ttl := fs.Duration("ttl", 60*time.Second, "time-to-live of the lock")
+	shared := fs.Bool("shared", false, "acquire a shared lock (SLock) instead of an exclusive lock (XLock)")
+	maxShared := fs.Int("max-shared", -1, "maximum number of shared permits (-1 for unlimited, shared only)")
+	weight := fs.Int("weight", 1, "number of shared permits to take (shared only)")
+	wait := fs.Bool("wait", false, "wait until the lock is available instead of failing immediately")
+	timeout := fs.Duration("timeout", 0, "give up waiting after this duration (0 for no limit, with --wait)")
+	retryInterval := fs.Duration("retry-interval", pglock.DefaultRetryInterval, "interval between attempts (with --wait)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock acquire --name NAME [flags]\n\nAcquires a lock and leaves it held until it expires or is released.\nExits with %d if the lock is not available.\n\n", exitNotAcquired)
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+	if !requireString("name", *name) || !requireString("lock-id", *lockID) {
+		return exitUsage
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	if *wait && *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	metadata, err := json.Marshal(map[string]string{
+		"source": "pglock acquire",
+		"user":   currentUser(),
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	view := acquireView{
+		Name:   *name,
+		LockID: *lockID,
+		Mode:   string(pglock.LockModeExclusive),
+	}
+	if *shared {
+		view.Mode = string(pglock.LockModeShared)
+	}
+
+	view.Acquired, view.ExpiresAt, err = acquire(ctx, client, acquireParams{
+		name:          *name,
+		lockID:        *lockID,
+		ttlSeconds:    ttlSeconds(*ttl),
+		shared:        *shared,
+		maxShared:     *maxShared,
+		weight:        *weight,
+		wait:          *wait,
+		retryInterval: *retryInterval,
+		metadata:      metadata,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	code := printResult(global.format, view, func() {
+		printAcquire(view)
+	})
+	if code == exitOK && !view.Acquired {
+		return exitNotAcquired
+	}
+
+	return code
+}
+
+type acquireParams struct {
+	name          string
+	lockID        string
+	ttlSeconds    int
+	shared        bool
+	maxShared     int
+	weight        int
+	wait          bool
+	retryInterval time.Duration
+	metadata      json.RawMessage
+}
+
+// acquire takes an XLock or SLock, blocking or not, and returns whether it was acquired and its expiration time.
+func acquire(ctx context.Context, client pglock.LockClient, params acquireParams) (bool, time.Time, error) {
+	switch {
+	case params.shared && params.wait:
+		result, err := client.SLock(ctx, pglock.SLockParams{
+			Name:             params.name,
+			LockID:           params.lockID,
+			TTLSeconds:       params.ttlSeconds,
+			MaxSharedLocks:   params.maxShared,
+			Weight:           params.weight,
+			IntervalDuration: params.retryInterval,
+			Metadata:         params.metadata,
+		})
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		return true, result.ExpiresAt, nil
+	case params.shared:
+		result, err := client.TrySLock(ctx, pglock.TrySLockParams{
+			Name:           params.name,
+			LockID:         params.lockID,
+			TTLSeconds:     params.ttlSeconds,
+			MaxSharedLocks: params.maxShared,
+			Weight:         params.weight,
+			Metadata:       params.metadata,
+		})
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		return result.Acquired, result.ExpiresAt, nil
+	case params.wait:
+		result, err := client.XLock(ctx, pglock.XLockParams{
+			Name:             params.name,
+			LockID:           params.lockID,
+			TTLSeconds:       params.ttlSeconds,
+			IntervalDuration: params.retryInterval,
+			Metadata:         params.metadata,
+		})
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		return true, result.ExpiresAt, nil
+	default:
+		result, err := client.TryXLock(ctx, pglock.TryXLockParams{
+			Name:       params.name,
+			LockID:     params.lockID,
+			TTLSeconds: params.ttlSeconds,
+			Metadata:   params.metadata,
+		})
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		return result.Acquired, result.ExpiresAt, nil
+	}
+}
+
+func runRelease(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("release")
+	name := fs.String("name", "", "lock name (required)")
+	lockID := fs.String("lock-id", "", "lock id the lock was acquired with (required)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock release --name NAME --lock-id ID [flags]\n\nReleases a lock held by the lock id.\nExits with %d if the lock id held nothing.\n\n", exitNotAcquired)
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+	if !requireString("name", *name) || !requireString("lock-id", *lockID) {
+		return exitUsage
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := client.Unlock(ctx, pglock.UnlockParams{
+		Name:   *name,
+		LockID: *lockID,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	view := releaseView{
+		Name:     *name,
+		LockID:   *lockID,
+		Released: result.Released,
+	}
+
+	code := printResult(global.format, view, func() {
+		printRelease(view)
+	})
+	if code == exitOK && !view.Released {
+		return exitNotAcquired
+	}
+
+	return code
+}
+
+func runHistory(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("history")
+	name := fs.String("name", "", "lock name (required)")
+	since := fs.Duration("since", 24*time.Hour, "show events newer than this duration")
+	limit := fs.Int("limit", 100, "maximum number of events")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock history --name NAME --audit [flags]\n\nShows the audit history of a lock.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+	if !requireString("name", *name) {
+		return exitUsage
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := client.GetLockHistory(ctx, pglock.GetLockHistoryParams{
+		Name:  *name,
+		From:  time.Now().Add(-*since),
+		Limit: *limit,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	views := make([]auditEventView, 0, len(result.Events))
+	for _, event := range result.Events {
+		views = append(views, newAuditEventView(event))
+	}
+
+	return printResult(global.format, views, func() {
+		printHistory(views)
+	})
+}
+
+// ttlSeconds converts a TTL to whole seconds, rounding up so short TTLs are not truncated to zero.
+func ttlSeconds(ttl time.Duration) int {
+	return int(math.Ceil(ttl.Seconds()))
+}
+
+func defaultLockID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/myyrakle/pglock"
+)
+
+// forwardedSignals are passed on to the child process while it runs
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+// runExec runs a command while holding a lock, like flock(1) does for local files.
+func runExec(ctx context.Context, args []string) int {
+	fs, global := newFlagSet("exec")
+	name := fs.String("name", "", "lock name (required)")
+	lockID := fs.String("lock-id", defaultLockID(), "lock id of the holder")
+	ttl := fs.Duration("ttl", 30*time.Second, "time-to-live of the lock, renewed while the command runs")
+	renewInterval := fs.Duration("renew-interval", 0, "interval between renewals (default: ttl / 3)")
+	shared := fs.Bool("shared", false, "hold a shared lock (SLock) instead of an exclusive lock (XLock)")
+	maxShared := fs.Int("max-shared", -1, "maximum number of shared permits (-1 for unlimited, shared only)")
+	weight := fs.Int("weight", 1, "number of shared permits to take (shared only)")
+	nonblock := fs.Bool("nonblock", false, "fail immediately instead of waiting if the lock is not available")
+	timeout := fs.Duration("timeout", 0, "give up waiting for the lock after this duration (0 for no limit)")
+	retryInterval := fs.Duration("retry-interval", pglock.DefaultRetryInterval, "interval between attempts while waiting")
+	conflictExitCode := fs.Int("conflict-exit-code", exitNotAcquired, "exit code when the lock is not available (--nonblock or --timeout)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pglock exec --name NAME [flags] -- COMMAND [ARGS...]\n\n"+
+			"Runs COMMAND while holding the lock. The lock is renewed while the command runs and\n"+
+			"released when it exits. Signals are forwarded to the command, and its exit code is returned.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := global.parse(args); !ok {
+		return code
+	}
+	if !requireString("name", *name) || !requireString("lock-id", *lockID) {
+		return exitUsage
+	}
+
+	commandArgs := fs.Args()
+	if len(commandArgs) == 0 {
+		fmt.Fprintln(os.Stderr, "pglock: no command to run")
+		return exitUsage
+	}
+
+	client, err := global.connect()
+	if err != nil {
+		return fail(err)
+	}
+
+	// 1. 락 획득
+	acquireCtx := ctx
+	if *timeout > 0 && !*nonblock {
+		var cancel context.CancelFunc
+		acquireCtx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	acquired, expiresAt, err := acquire(acquireCtx, client, acquireParams{
+		name:          *name,
+		lockID:        *lockID,
+		ttlSeconds:    ttlSeconds(*ttl),
+		shared:        *shared,
+		maxShared:     *maxShared,
+		weight:        *weight,
+		wait:          !*nonblock,
+		retryInterval: *retryInterval,
+	})
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		// --timeout 초과는 획득 실패로 취급
+		acquired, err = false, nil
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if !acquired {
+		fmt.Fprintf(os.Stderr, "pglock: %s is not available\n", *name)
+		return *conflictExitCode
+	}
+
+	// 2. 명령 실행 중 락 갱신 (시그널로 취소되는 ctx와 분리)
+	holdCtx, stopHolding := context.WithCancel(context.Background())
+	defer stopHolding()
+
+	defer func() {
+		stopHolding()
+
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, err := client.Unlock(releaseCtx, pglock.UnlockParams{Name: *name, LockID: *lockID}); err != nil {
+			fmt.Fprintf(os.Stderr, "pglock: failed to release %s: %v\n", *name, err)
+		}
+	}()
+
+	lost := pglock.KeepAlive(holdCtx, client, pglock.KeepAliveParams{
+		Name:             *name,
+		LockID:           *lockID,
+		TTLSeconds:       ttlSeconds(*ttl),
+		ExpiresAt:        expiresAt,
+		IntervalDuration: *renewInterval,
+	})
+
+	// 3. 명령 실행 및 시그널 전달
+	cmd := exec.Command(commandArgs[0], commandArgs[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return fail(err)
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+
+	for {
+		select {
+		case sig := <-signals:
+			_ = cmd.Process.Signal(sig)
+		case err, ok := <-lost:
+			if !ok {
+				// 갱신 중단 (정상 종료 경로)
+				lost = nil
+				continue
+			}
+			// 상호 배제가 더 이상 보장되지 않으므로 명령을 중단
+			fmt.Fprintf(os.Stderr, "pglock: %v, terminating command\n", err)
+			_ = cmd.Process.Signal(syscall.SIGTERM)
+			lost = nil
+		case err := <-waitErr:
+			return exitCodeOf(err)
+		}
+	}
+}
+
+// exitCodeOf returns the exit code of a finished command, following the shell convention of 128+signal.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fail(err)
+	}
+
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+
+	return exitErr.ExitCode()
+}
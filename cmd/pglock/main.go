@@ -0,0 +1,174 @@
+// Command pglock operates the pglock lock table: it sets up tables, inspects and
+// force-unlocks locks, acquires or releases locks by hand, and runs commands under a lock.
+//
+// The database URL is read from --dsn or the PGLOCK_DATABASE_URL environment variable.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/myyrakle/pglock"
+)
+
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotAcquired = 3 // acquire could not get the lock, or release found nothing to release
+)
+
+// DatabaseURLEnv is the environment variable used when --dsn is not given
+const DatabaseURLEnv = "PGLOCK_DATABASE_URL"
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) int
+}
+
+func commands() []command {
+	return []command{
+		{name: "setup", summary: "Create the lock tables", run: runSetup},
+		{name: "list", summary: "List locks and their holders", run: runList},
+		{name: "namespaces", summary: "List namespaces and their lock counts", run: runNamespaces},
+		{name: "describe", summary: "Show the holders of a lock", run: runDescribe},
+		{name: "force-unlock", summary: "Release every holder of a lock", run: runForceUnlock},
+		{name: "acquire", summary: "Acquire a lock (XLock, or SLock with --shared)", run: runAcquire},
+		{name: "release", summary: "Release a lock held by a lock id", run: runRelease},
+		{name: "history", summary: "Show the audit history of a lock", run: runHistory},
+		{name: "exec", summary: "Run a command while holding a lock (like flock(1))", run: runExec},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "pglock: unknown command %q\n\n", args[0])
+	printUsage()
+	return exitUsage
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: pglock <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'pglock <command> -h' for the flags of a command.\n")
+	fmt.Fprintf(os.Stderr, "The database URL is read from --dsn or $%s.\n", DatabaseURLEnv)
+}
+
+// globalFlags are the flags shared by every command
+type globalFlags struct {
+	dsn         string
+	lockTable   string
+	policyTable string
+	auditTable  string
+	namespace   string
+	enableAudit bool
+	format      string
+	flagSet     *flag.FlagSet
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *globalFlags) {
+	fs := flag.NewFlagSet("pglock "+name, flag.ContinueOnError)
+	global := &globalFlags{flagSet: fs}
+
+	fs.StringVar(&global.dsn, "dsn", os.Getenv(DatabaseURLEnv), "PostgreSQL URL (default: $"+DatabaseURLEnv+")")
+	fs.StringVar(&global.lockTable, "table", "", `lock table name (default: "lock")`)
+	fs.StringVar(&global.policyTable, "policy-table", "", `lock policy table name (default: "lock_policy")`)
+	fs.StringVar(&global.auditTable, "audit-table", "", `audit table name (default: "lock_audit")`)
+	fs.StringVar(&global.namespace, "namespace", "", "namespace of the lock names (default: none)")
+	fs.BoolVar(&global.enableAudit, "audit", false, "record changes in the audit table (required by history)")
+	fs.StringVar(&global.format, "format", formatTable, "output format: table or json")
+
+	return fs, global
+}
+
+// parse parses the flags and validates the shared ones. It returns an exit code and false on failure.
+func (g *globalFlags) parse(args []string) (int, bool) {
+	if err := g.flagSet.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+
+	if g.format != formatTable && g.format != formatJSON {
+		fmt.Fprintf(os.Stderr, "pglock: unknown format %q (use %q or %q)\n", g.format, formatTable, formatJSON)
+		return exitUsage, false
+	}
+	if g.dsn == "" {
+		fmt.Fprintf(os.Stderr, "pglock: no database URL, set --dsn or $%s\n", DatabaseURLEnv)
+		return exitUsage, false
+	}
+	if strings.Contains(g.namespace, pglock.NamespaceSeparator) {
+		fmt.Fprintf(os.Stderr, "pglock: --namespace must not contain %q\n", pglock.NamespaceSeparator)
+		return exitUsage, false
+	}
+
+	return exitOK, true
+}
+
+func (g *globalFlags) connect() (pglock.LockClient, error) {
+	client := pglock.NewLockClient(g.clientOptions())
+
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (g *globalFlags) clientOptions() pglock.LockClientOptions {
+	return pglock.LockClientOptions{
+		DatabaseURL:         g.dsn,
+		MaxOpenConnections:  2,
+		MaxIdleConnections:  1,
+		LockTableName:       g.lockTable,
+		LockPolicyTableName: g.policyTable,
+		LockAuditTableName:  g.auditTable,
+		Namespace:           g.namespace,
+		EnableAudit:         g.enableAudit,
+	}
+}
+
+// requireString reports a usage error if a required string flag is empty.
+func requireString(name string, value string) bool {
+	if value == "" {
+		fmt.Fprintf(os.Stderr, "pglock: --%s is required\n", name)
+		return false
+	}
+
+	return true
+}
+
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "pglock: %v\n", err)
+	return exitError
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/myyrakle/pglock"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+type lockView struct {
+	Name           string            `json:"name"`
+	Mode           string            `json:"mode"` // "exclusive", "shared" or "free"
+	XLockID        string            `json:"xlock_id,omitempty"`
+	XExpiresAt     *time.Time        `json:"x_expires_at,omitempty"`
+	XOwner         *pglock.OwnerInfo `json:"x_owner,omitempty"`
+	SharedLocks    []sharedLockView  `json:"shared_locks"`
+	MaxSharedLocks int               `json:"max_shared_locks"`
+}
+
+type sharedLockView struct {
+	LockID    string            `json:"lock_id"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	Weight    int               `json:"weight"`
+	Owner     *pglock.OwnerInfo `json:"owner,omitempty"`
+}
+
+func newLockView(lock pglock.LockInfo) lockView {
+	view := lockView{
+		Name:           lock.Name,
+		Mode:           "free",
+		SharedLocks:    []sharedLockView{},
+		MaxSharedLocks: lock.MaxSharedLocks,
+	}
+
+	if lock.XLockID != "" {
+		expiresAt := lock.XExpiresAt
+		view.Mode = string(pglock.LockModeExclusive)
+		view.XLockID = lock.XLockID
+		view.XExpiresAt = &expiresAt
+		view.XOwner = lock.XOwner
+	}
+
+	for _, entry := range lock.SharedLocks {
+		view.Mode = string(pglock.LockModeShared)
+		view.SharedLocks = append(view.SharedLocks, sharedLockView{
+			LockID:    entry.LockID,
+			ExpiresAt: entry.ExpiresAt,
+			Weight:    entry.PermitWeight(),
+			Owner:     entry.Owner,
+		})
+	}
+
+	return view
+}
+
+type namespaceView struct {
+	Namespace string `json:"namespace"`
+	Locks     int    `json:"locks"`
+	HeldLocks int    `json:"held_locks"`
+}
+
+type forceUnlockView struct {
+	Name          string   `json:"name"`
+	Released      bool     `json:"released"`
+	XLockID       string   `json:"xlock_id,omitempty"`
+	SharedLockIDs []string `json:"shared_lock_ids"`
+}
+
+type acquireView struct {
+	Name      string    `json:"name"`
+	LockID    string    `json:"lock_id"`
+	Mode      string    `json:"mode"`
+	Acquired  bool      `json:"acquired"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+type releaseView struct {
+	Name     string `json:"name"`
+	LockID   string `json:"lock_id"`
+	Released bool   `json:"released"`
+}
+
+type auditEventView struct {
+	ID         int64           `json:"id"`
+	Event      string          `json:"event"`
+	LockID     string          `json:"lock_id"`
+	Mode       string          `json:"mode"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	ExpiresAt  time.Time       `json:"expires_at,omitzero"`
+	Metadata   json.RawMessage `json:"metadata,omitempty"`
+}
+
+func newAuditEventView(event pglock.LockAuditEvent) auditEventView {
+	return auditEventView{
+		ID:         event.ID,
+		Event:      string(event.Event),
+		LockID:     event.LockID,
+		Mode:       string(event.Mode),
+		OccurredAt: event.OccurredAt,
+		ExpiresAt:  event.ExpiresAt,
+		Metadata:   event.Metadata,
+	}
+}
+
+// printResult writes v as JSON, or calls printTable for the table format.
+func printResult(format string, v any, printTable func()) int {
+	if format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(v); err != nil {
+			return fail(err)
+		}
+		return exitOK
+	}
+
+	printTable()
+	return exitOK
+}
+
+func newTabWriter() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%s (in %s)", t.Local().Format(time.RFC3339), time.Until(t).Round(time.Second))
+}
+
+func formatMaxShared(maxSharedLocks int) string {
+	if maxSharedLocks < 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprint(maxSharedLocks)
+}
+
+// formatOwner shows where a holder runs as "hostname:pid", or "-" if it was not recorded.
+func formatOwner(owner *pglock.OwnerInfo) string {
+	if owner == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%s:%d", owner.Hostname, owner.PID)
+}
+
+func printLockTable(views []lockView) {
+	w := newTabWriter()
+	fmt.Fprintln(w, "NAME\tMODE\tHOLDERS\tPERMITS\tEXPIRES")
+
+	for _, view := range views {
+		holders := []string{}
+		expiresAt := time.Time{}
+		permits := 0
+
+		if view.XExpiresAt != nil {
+			holders = append(holders, view.XLockID)
+			expiresAt = *view.XExpiresAt
+		}
+		for _, entry := range view.SharedLocks {
+			holders = append(holders, entry.LockID)
+			permits += entry.Weight
+			// 가장 먼저 만료되는 보유자 기준
+			if expiresAt.IsZero() || entry.ExpiresAt.Before(expiresAt) {
+				expiresAt = entry.ExpiresAt
+			}
+		}
+
+		holderText := strings.Join(holders, ",")
+		if holderText == "" {
+			holderText = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%s\t%s\n",
+			view.Name, view.Mode, holderText, permits, formatMaxShared(view.MaxSharedLocks), formatTime(expiresAt))
+	}
+
+	w.Flush()
+}
+
+func printLockDetail(view lockView) {
+	fmt.Printf("Name:             %s\n", view.Name)
+	fmt.Printf("Mode:             %s\n", view.Mode)
+	fmt.Printf("Max shared locks: %s\n", formatMaxShared(view.MaxSharedLocks))
+
+	if view.XExpiresAt == nil && len(view.SharedLocks) == 0 {
+		fmt.Println("Holders:          none")
+		return
+	}
+
+	fmt.Println("Holders:")
+	w := newTabWriter()
+	fmt.Fprintln(w, "  MODE\tLOCK ID\tWEIGHT\tOWNER\tEXPIRES")
+	if view.XExpiresAt != nil {
+		fmt.Fprintf(w, "  %s\t%s\t-\t%s\t%s\n",
+			pglock.LockModeExclusive, view.XLockID, formatOwner(view.XOwner), formatTime(*view.XExpiresAt))
+	}
+	for _, entry := range view.SharedLocks {
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%s\t%s\n",
+			pglock.LockModeShared, entry.LockID, entry.Weight, formatOwner(entry.Owner), formatTime(entry.ExpiresAt))
+	}
+	w.Flush()
+}
+
+func printNamespaces(views []namespaceView) {
+	w := newTabWriter()
+	fmt.Fprintln(w, "NAMESPACE\tLOCKS\tHELD")
+
+	for _, view := range views {
+		namespace := view.Namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%d\n", namespace, view.Locks, view.HeldLocks)
+	}
+
+	w.Flush()
+}
+
+func printForceUnlock(view forceUnlockView) {
+	if !view.Released {
+		fmt.Printf("%s had no live holders\n", view.Name)
+		return
+	}
+
+	holders := append([]string{}, view.SharedLockIDs...)
+	if view.XLockID != "" {
+		holders = append([]string{view.XLockID}, holders...)
+	}
+	fmt.Printf("released %s from %s\n", view.Name, strings.Join(holders, ", "))
+}
+
+func printAcquire(view acquireView) {
+	if !view.Acquired {
+		fmt.Printf("%s is not available\n", view.Name)
+		return
+	}
+
+	fmt.Printf("acquired %s lock %s as %s, expires %s\n", view.Mode, view.Name, view.LockID, formatTime(view.ExpiresAt))
+}
+
+func printRelease(view releaseView) {
+	if !view.Released {
+		fmt.Printf("%s was not held by %s\n", view.Name, view.LockID)
+		return
+	}
+
+	fmt.Printf("released %s held by %s\n", view.Name, view.LockID)
+}
+
+func printHistory(views []auditEventView) {
+	w := newTabWriter()
+	fmt.Fprintln(w, "TIME\tEVENT\tMODE\tLOCK ID\tEXPIRES\tMETADATA")
+
+	for _, view := range views {
+		expiresAt := "-"
+		if !view.ExpiresAt.IsZero() {
+			expiresAt = view.ExpiresAt.Local().Format(time.RFC3339)
+		}
+		metadata := "-"
+		if len(view.Metadata) > 0 {
+			metadata = string(view.Metadata)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			view.OccurredAt.Local().Format(time.RFC3339), view.Event, view.Mode, view.LockID, expiresAt, metadata)
+	}
+
+	w.Flush()
+}
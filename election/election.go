@@ -0,0 +1,226 @@
+// Package election implements leader election on top of a pglock exclusive lock.
+//
+// The leader is the candidate holding the XLock for the election name. Its lease is
+// kept alive with pglock.KeepAlive, and any candidate can read the current leader
+// from the lock row.
+package election
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/myyrakle/pglock"
+)
+
+const (
+	// DefaultTTLSeconds is the default lease TTL of the leader
+	DefaultTTLSeconds = 15
+	// DefaultRetryInterval is the default interval between campaign attempts
+	DefaultRetryInterval = 1 * time.Second
+)
+
+type Options struct {
+	Name          string        // [required] Lock name the candidates campaign for
+	CandidateID   string        // [required] Identifier of this candidate, stored as the LockID of the lock
+	TTLSeconds    int           // [optional] Lease TTL of the leader. default: 15
+	RenewInterval time.Duration // [optional] Lease renewal interval. default: TTL / 3
+	RetryInterval time.Duration // [optional] Interval between campaign attempts. default: 1s
+}
+
+func (options *Options) SetDefaults() {
+	if options.TTLSeconds <= 0 {
+		options.TTLSeconds = DefaultTTLSeconds
+	}
+	if options.RenewInterval <= 0 {
+		options.RenewInterval = time.Duration(options.TTLSeconds) * time.Second / 3
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = DefaultRetryInterval
+	}
+}
+
+// Election campaigns for leadership of a single name.
+type Election struct {
+	client  pglock.LockClient
+	options Options
+
+	mu          sync.Mutex
+	leader      bool
+	stopRenewal context.CancelFunc
+	renewalDone chan struct{}
+
+	changes chan bool
+}
+
+func New(client pglock.LockClient, options Options) *Election {
+	options.SetDefaults()
+
+	return &Election{
+		client:  client,
+		options: options,
+		changes: make(chan bool, 1),
+	}
+}
+
+// Campaign blocks until this candidate becomes the leader or ctx is done.
+// Once elected, the lease is renewed in the background until Resign is called or the lease is lost.
+func (e *Election) Campaign(ctx context.Context) error {
+	if e.IsLeader() {
+		return nil
+	}
+
+	result, err := e.client.XLock(ctx, pglock.XLockParams{
+		Name:             e.options.Name,
+		LockID:           e.options.CandidateID,
+		TTLSeconds:       e.options.TTLSeconds,
+		IntervalDuration: e.options.RetryInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	renewalCtx, stopRenewal := context.WithCancel(context.Background())
+	renewalDone := make(chan struct{})
+
+	e.mu.Lock()
+	e.stopRenewal = stopRenewal
+	e.renewalDone = renewalDone
+	e.setLeaderLocked(true)
+	e.mu.Unlock()
+
+	lost := pglock.KeepAlive(renewalCtx, e.client, pglock.KeepAliveParams{
+		Name:             e.options.Name,
+		LockID:           e.options.CandidateID,
+		TTLSeconds:       e.options.TTLSeconds,
+		ExpiresAt:        result.ExpiresAt,
+		IntervalDuration: e.options.RenewInterval,
+	})
+
+	go func() {
+		defer close(renewalDone)
+
+		if _, ok := <-lost; !ok {
+			// Resign으로 중단됨
+			return
+		}
+
+		e.mu.Lock()
+		// 그 사이에 Resign 후 다시 선출되었다면 새 임기는 건드리지 않음
+		if e.renewalDone == renewalDone {
+			e.stopRenewal = nil
+			e.renewalDone = nil
+			e.setLeaderLocked(false)
+		}
+		e.mu.Unlock()
+		stopRenewal()
+	}()
+
+	return nil
+}
+
+// Resign gives up leadership voluntarily and releases the lock so another candidate can be elected.
+// It does nothing if this candidate is not the leader.
+func (e *Election) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	stopRenewal, renewalDone := e.stopRenewal, e.renewalDone
+	e.stopRenewal = nil
+	e.renewalDone = nil
+	wasLeader := e.leader
+	e.setLeaderLocked(false)
+	e.mu.Unlock()
+
+	if !wasLeader {
+		return nil
+	}
+
+	if stopRenewal != nil {
+		stopRenewal()
+		<-renewalDone
+	}
+
+	_, err := e.client.Unlock(ctx, pglock.UnlockParams{
+		Name:   e.options.Name,
+		LockID: e.options.CandidateID,
+	})
+
+	return err
+}
+
+// Run campaigns repeatedly until ctx is done, regaining leadership whenever it is lost.
+// Leadership is resigned before Run returns.
+func (e *Election) Run(ctx context.Context) error {
+	for {
+		if err := e.Campaign(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		e.mu.Lock()
+		renewalDone := e.renewalDone
+		e.mu.Unlock()
+
+		if renewalDone == nil {
+			// 선출 직후 이미 리더십을 잃음
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			// 종료 시에는 새 컨텍스트로 해제
+			resignCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := e.Resign(resignCtx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-renewalDone:
+			// 리더십 상실, 다시 선거 참여
+		}
+	}
+}
+
+// IsLeader reports whether this candidate currently holds the leadership.
+func (e *Election) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.leader
+}
+
+// Leader returns the CandidateID of the current leader as recorded in the lock row,
+// or "" if nobody holds the leadership. It can be called by any candidate or observer.
+func (e *Election) Leader(ctx context.Context) (string, error) {
+	result, err := e.client.DescribeLock(ctx, pglock.DescribeLockParams{
+		Name: e.options.Name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.Lock.XLockID, nil
+}
+
+// Changes returns a channel that receives the new leadership state of this candidate
+// every time it changes. Only the latest state is kept if the receiver falls behind.
+func (e *Election) Changes() <-chan bool {
+	return e.changes
+}
+
+// setLeaderLocked updates the leadership state and notifies Changes. e.mu must be held.
+func (e *Election) setLeaderLocked(leader bool) {
+	if e.leader == leader {
+		return
+	}
+	e.leader = leader
+
+	// 밀린 상태는 버리고 최신 상태만 유지
+	select {
+	case <-e.changes:
+	default:
+	}
+	e.changes <- leader
+}
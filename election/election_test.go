@@ -0,0 +1,71 @@
+package election
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myyrakle/pglock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestClient(t *testing.T) pglock.LockClient {
+	client := pglock.NewLockClient(pglock.LockClientOptions{
+		DatabaseURL: "postgres://postgres@localhost:5432/postgres?sslmode=disable",
+	})
+
+	if err := client.Initialize(); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	return client
+}
+
+// TestElection_SingleLeader tests that only one candidate is elected and that resigning hands over leadership
+func TestElection_SingleLeader(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	first := New(client, Options{
+		Name:          "test_election",
+		CandidateID:   "candidate_1",
+		TTLSeconds:    5,
+		RetryInterval: 50 * time.Millisecond,
+	})
+	second := New(client, Options{
+		Name:          "test_election",
+		CandidateID:   "candidate_2",
+		TTLSeconds:    5,
+		RetryInterval: 50 * time.Millisecond,
+	})
+
+	// 1. 첫 번째 후보 선출
+	require.NoError(t, first.Campaign(ctx))
+	assert.True(t, first.IsLeader())
+	assert.True(t, <-first.Changes())
+
+	leader, err := second.Leader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "candidate_1", leader)
+
+	// 2. 두 번째 후보는 선출되지 않아야 함
+	campaignCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, second.Campaign(campaignCtx), context.DeadlineExceeded)
+	assert.False(t, second.IsLeader())
+
+	// 3. 사임 후 두 번째 후보 선출
+	require.NoError(t, first.Resign(ctx))
+	assert.False(t, first.IsLeader())
+	assert.False(t, <-first.Changes())
+
+	require.NoError(t, second.Campaign(ctx))
+	assert.True(t, second.IsLeader())
+
+	leader, err = first.Leader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "candidate_2", leader)
+
+	require.NoError(t, second.Resign(ctx))
+}
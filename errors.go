@@ -0,0 +1,27 @@
+package pglock
+
+import "errors"
+
+var (
+	// ErrLockLost is reported when a held lock could not be refreshed before it expired
+	ErrLockLost = errors.New("pglock: lock lost")
+
+	// ErrAuditDisabled is returned by the audit history APIs when EnableAudit is not set
+	ErrAuditDisabled = errors.New("pglock: audit is not enabled")
+
+	// ErrTxLocksDisabled is returned by the transaction-scoped lock APIs when EnableTxLocks is not set
+	ErrTxLocksDisabled = errors.New("pglock: transaction-scoped locks are not enabled")
+
+	// ErrSessionLost is returned when a lock is requested under a session that is no longer alive,
+	// and reported when a session could not be refreshed before it expired
+	ErrSessionLost = errors.New("pglock: session lost")
+
+	// ErrNotLockHolder is returned by GetValue and SetValue when the lock id does not hold the exclusive lock
+	ErrNotLockHolder = errors.New("pglock: lock is not held by the lock id")
+
+	// ErrTaskFailed is returned by RunOnce when the recorded run of the task failed, wrapping its error message
+	ErrTaskFailed = errors.New("pglock: task failed")
+
+	// ErrNotSupported is returned by LockClient implementations that cannot perform an operation
+	ErrNotSupported = errors.New("pglock: operation not supported by this client")
+)
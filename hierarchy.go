@@ -0,0 +1,353 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Hierarchical locks use multiple-granularity locking on "/"-separated names: locking
+// "tenant/42/invoice/7" also takes intention locks on "tenant" and "tenant/42", so that
+// an exclusive lock on "tenant/42" and a lock on any resource under it exclude each other.
+// They are stored in their own table and are independent of XLock/SLock on the same names.
+
+// HierarchySeparator separates the levels of a hierarchical lock name
+const HierarchySeparator = "/"
+
+// HierarchyMode is the mode of a hierarchical lock
+type HierarchyMode string
+
+const (
+	HierarchyModeIntentionShared          HierarchyMode = "IS"  // Intends to take S locks on descendants
+	HierarchyModeIntentionExclusive       HierarchyMode = "IX"  // Intends to take X (or S) locks on descendants
+	HierarchyModeShared                   HierarchyMode = "S"   // Reads the node and all of its descendants
+	HierarchyModeSharedIntentionExclusive HierarchyMode = "SIX" // S on the node plus IX for descendants
+	HierarchyModeExclusive                HierarchyMode = "X"   // Exclusive access to the node and all of its descendants
+)
+
+// hierarchyCompatibility is the standard multiple-granularity compatibility matrix
+var hierarchyCompatibility = map[HierarchyMode]map[HierarchyMode]bool{
+	HierarchyModeIntentionShared: {
+		HierarchyModeIntentionShared: true, HierarchyModeIntentionExclusive: true,
+		HierarchyModeShared: true, HierarchyModeSharedIntentionExclusive: true,
+	},
+	HierarchyModeIntentionExclusive: {
+		HierarchyModeIntentionShared: true, HierarchyModeIntentionExclusive: true,
+	},
+	HierarchyModeShared: {
+		HierarchyModeIntentionShared: true, HierarchyModeShared: true,
+	},
+	HierarchyModeSharedIntentionExclusive: {
+		HierarchyModeIntentionShared: true,
+	},
+	HierarchyModeExclusive: {},
+}
+
+// Compatible reports whether the mode can be held on a node together with other, by different holders.
+func (mode HierarchyMode) Compatible(other HierarchyMode) bool {
+	return hierarchyCompatibility[mode][other]
+}
+
+// intention returns the mode taken on the ancestors of a node locked in mode.
+func (mode HierarchyMode) intention() HierarchyMode {
+	switch mode {
+	case HierarchyModeIntentionShared, HierarchyModeShared:
+		return HierarchyModeIntentionShared
+	default:
+		return HierarchyModeIntentionExclusive
+	}
+}
+
+func (mode HierarchyMode) valid() bool {
+	_, ok := hierarchyCompatibility[mode]
+	return ok
+}
+
+// validHierarchyName reports whether every segment of name is non-empty, so that each node has its own name.
+func validHierarchyName(name string) bool {
+	return !slices.Contains(strings.Split(name, HierarchySeparator), "")
+}
+
+// hierarchyRequest is a lock to take on a single node of the hierarchy
+type hierarchyRequest struct {
+	name string
+	mode HierarchyMode
+}
+
+// hierarchyRequests returns the locks needed to lock name in mode, from the root down to name.
+func hierarchyRequests(name string, mode HierarchyMode) []hierarchyRequest {
+	parts := strings.Split(name, HierarchySeparator)
+	requests := make([]hierarchyRequest, 0, len(parts))
+
+	for i := 1; i < len(parts); i++ {
+		requests = append(requests, hierarchyRequest{
+			name: strings.Join(parts[:i], HierarchySeparator),
+			mode: mode.intention(),
+		})
+	}
+	requests = append(requests, hierarchyRequest{name: name, mode: mode})
+
+	return requests
+}
+
+// HierarchyHolder is a live lock on a node of the hierarchy
+type HierarchyHolder struct {
+	Name      string        // Node the lock is on
+	LockID    string        // Holder of the lock
+	Target    string        // Name the holder requested (Name itself, or a descendant for intention locks)
+	Mode      HierarchyMode // Mode held on the node
+	ExpiresAt time.Time     // Expiration time of the lock
+}
+
+type TryHierarchyLockParams struct {
+	Name       string        // Lock Name: "/"-separated path without empty segments, e.g. "tenant/42/invoice/7"
+	LockID     string        // Lock LockID: identifier for the entity requesting the lock
+	Mode       HierarchyMode // Mode to take on Name (ancestors get the matching intention mode)
+	TTLSeconds int           // Time-To-Live: duration in seconds for the lock
+}
+
+type TryHierarchyLockResult struct {
+	ExpiresAt time.Time       // Expiration time of the lock
+	Acquired  bool            // Whether the lock was acquired
+	Conflict  HierarchyHolder // The holder that prevented the acquisition, if not acquired
+}
+
+type HierarchyLockParams struct {
+	Name             string        // Lock Name: "/"-separated path without empty segments, e.g. "tenant/42/invoice/7"
+	LockID           string        // Lock LockID: identifier for the entity requesting the lock
+	Mode             HierarchyMode // Mode to take on Name (ancestors get the matching intention mode)
+	TTLSeconds       int           // Time-To-Live: duration in seconds for the lock
+	IntervalDuration time.Duration // Retry interval duration (default value: 100ms)
+}
+
+type HierarchyLockResult struct {
+	ExpiresAt time.Time // Expiration time of the lock
+}
+
+type HierarchyUnlockParams struct {
+	Name   string // Lock Name passed to TryHierarchyLock or HierarchyLock
+	LockID string // Lock LockID: identifier for the entity releasing the lock
+}
+
+type HierarchyUnlockResult struct {
+	Released bool // Whether a live lock was released
+}
+
+func (c *lockClient) createHierarchyLockTable(ctx context.Context) error {
+	tableName := c.options.HierarchyLockTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			name TEXT NOT NULL,
+			lock_id TEXT NOT NULL,
+			target TEXT NOT NULL,
+			mode TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (name, lock_id, target)
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+	if err != nil {
+		return err
+	}
+
+	// 해제 시 (lock_id, target)으로 조회
+	createIndexSQL := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_holder ON %s (lock_id, target);
+	`, tableName, tableName)
+
+	_, err = c.db.ExecContext(ctx, createIndexSQL)
+
+	return err
+}
+
+// TryHierarchyLock attempts to lock Name in Mode together with the intention locks on its ancestors.
+// Calling it again with the same Name and LockID renews the lock (and may change its mode).
+func (c *lockClient) TryHierarchyLock(ctx context.Context, params TryHierarchyLockParams) (TryHierarchyLockResult, error) {
+	if !params.Mode.valid() {
+		return TryHierarchyLockResult{}, fmt.Errorf("pglock: unknown hierarchy mode %q", params.Mode)
+	}
+	if !validHierarchyName(params.Name) {
+		return TryHierarchyLockResult{}, fmt.Errorf("pglock: hierarchy lock name %q has an empty path segment", params.Name)
+	}
+
+	transaction, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TryHierarchyLockResult{}, err
+	}
+	defer transaction.Rollback()
+
+	tableName := c.options.HierarchyLockTableName
+	requests := hierarchyRequests(params.Name, params.Mode)
+	now := time.Now()
+
+	// 1. 루트부터 순서대로 노드별 advisory 잠금 - 같은 순서이므로 교착 상태 없음
+	for _, request := range requests {
+		if _, err := transaction.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1);`, c.advisoryKey("hierarchy", request.name)); err != nil {
+			return TryHierarchyLockResult{}, err
+		}
+	}
+
+	// 2. 경로의 각 노드에서 다른 보유자와 호환되는지 확인
+	selectQuery := fmt.Sprintf(`
+		SELECT lock_id, target, mode, expires_at
+		FROM %s
+		WHERE name = $1 AND lock_id <> $2 AND expires_at > $3;
+	`, tableName)
+
+	for _, request := range requests {
+		conflict, found, err := c.findHierarchyConflict(ctx, transaction, selectQuery, request, params.LockID, now)
+		if err != nil {
+			return TryHierarchyLockResult{}, err
+		}
+		if found {
+			c.logHierarchyConflict(ctx, params.Mode, params.Name, params.LockID, conflict)
+			return TryHierarchyLockResult{Acquired: false, Conflict: conflict}, nil
+		}
+	}
+
+	// 3. 같은 보유자의 이전 기록 삭제 (갱신 또는 모드 변경)
+	deleteOwnQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE lock_id = $1 AND target = $2;
+	`, tableName)
+	if _, err := transaction.ExecContext(ctx, deleteOwnQuery, params.LockID, params.Name); err != nil {
+		return TryHierarchyLockResult{}, err
+	}
+
+	// 4. 경로의 만료된 보유자 정리 후 노드별 잠금 기록
+	deleteExpiredQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE name = $1 AND expires_at <= $2;
+	`, tableName)
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, lock_id, target, mode, expires_at)
+		VALUES ($1, $2, $3, $4, $5);
+	`, tableName)
+
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+	for _, request := range requests {
+		if _, err := transaction.ExecContext(ctx, deleteExpiredQuery, request.name, now); err != nil {
+			return TryHierarchyLockResult{}, err
+		}
+		if _, err := transaction.ExecContext(ctx, insertQuery, request.name, params.LockID, params.Name, request.mode, expiresAt); err != nil {
+			return TryHierarchyLockResult{}, err
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return TryHierarchyLockResult{}, err
+	}
+
+	c.logHierarchyAcquired(ctx, params.Mode, params.Name, params.LockID, expiresAt)
+
+	return TryHierarchyLockResult{ExpiresAt: expiresAt, Acquired: true}, nil
+}
+
+// findHierarchyConflict returns a live holder of another LockID on the node that is incompatible with the request.
+func (c *lockClient) findHierarchyConflict(
+	ctx context.Context,
+	transaction *sql.Tx,
+	selectQuery string,
+	request hierarchyRequest,
+	lockID string,
+	now time.Time,
+) (HierarchyHolder, bool, error) {
+	rows, err := transaction.QueryContext(ctx, selectQuery, request.name, lockID, now)
+	if err != nil {
+		return HierarchyHolder{}, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		holder := HierarchyHolder{Name: request.name}
+		if err := rows.Scan(&holder.LockID, &holder.Target, &holder.Mode, &holder.ExpiresAt); err != nil {
+			return HierarchyHolder{}, false, err
+		}
+
+		if !request.mode.Compatible(holder.Mode) {
+			return holder, true, nil
+		}
+	}
+
+	return HierarchyHolder{}, false, rows.Err()
+}
+
+// HierarchyLock continuously attempts to acquire a hierarchical lock until successful.
+func (c *lockClient) HierarchyLock(ctx context.Context, params HierarchyLockParams) (HierarchyLockResult, error) {
+	return hierarchyLock(ctx, c, &c.instrumentation, params)
+}
+
+// hierarchyLock retries TryHierarchyLock until the lock is acquired or ctx is done.
+func hierarchyLock(ctx context.Context, client LockClient, i *instrumentation, params HierarchyLockParams) (HierarchyLockResult, error) {
+	// IntervalDuration이 0 이하면 기본값 사용 (타이트 루프 방지)
+	if params.IntervalDuration <= 0 {
+		params.IntervalDuration = DefaultRetryInterval
+	}
+
+	for attempt := 1; ; attempt++ {
+		i.logHierarchyAttempt(ctx, params.Mode, params.Name, params.LockID, attempt)
+
+		result, err := client.TryHierarchyLock(ctx, TryHierarchyLockParams{
+			Name:       params.Name,
+			LockID:     params.LockID,
+			Mode:       params.Mode,
+			TTLSeconds: params.TTLSeconds,
+		})
+		if err != nil {
+			return HierarchyLockResult{}, err
+		}
+		if result.Acquired {
+			return HierarchyLockResult{ExpiresAt: result.ExpiresAt}, nil
+		}
+
+		// 컨텍스트 취소 확인
+		select {
+		case <-ctx.Done():
+			return HierarchyLockResult{}, ctx.Err()
+		case <-time.After(params.IntervalDuration):
+			// 재시도
+		}
+	}
+}
+
+// HierarchyUnlock releases a hierarchical lock and the intention locks taken for it.
+func (c *lockClient) HierarchyUnlock(ctx context.Context, params HierarchyUnlockParams) (HierarchyUnlockResult, error) {
+	tableName := c.options.HierarchyLockTableName
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE lock_id = $1 AND target = $2
+		RETURNING name, expires_at;
+	`, tableName)
+
+	rows, err := c.db.QueryContext(ctx, deleteQuery, params.LockID, params.Name)
+	if err != nil {
+		return HierarchyUnlockResult{}, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	released := false
+	for rows.Next() {
+		var name string
+		var expiresAt time.Time
+		if err := rows.Scan(&name, &expiresAt); err != nil {
+			return HierarchyUnlockResult{}, err
+		}
+		if name == params.Name && expiresAt.After(now) {
+			released = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return HierarchyUnlockResult{}, err
+	}
+
+	c.logReleased(ctx, params.Name, params.LockID, released)
+
+	return HierarchyUnlockResult{Released: released}, nil
+}
@@ -0,0 +1,94 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tryHierarchyLock(t *testing.T, client LockClient, name string, lockID string, mode HierarchyMode) TryHierarchyLockResult {
+	t.Helper()
+
+	result, err := client.TryHierarchyLock(context.Background(), TryHierarchyLockParams{
+		Name:       name,
+		LockID:     lockID,
+		Mode:       mode,
+		TTLSeconds: 60,
+	})
+	require.NoError(t, err)
+
+	return result
+}
+
+func hierarchyUnlock(t *testing.T, client LockClient, name string, lockID string) {
+	t.Helper()
+
+	result, err := client.HierarchyUnlock(context.Background(), HierarchyUnlockParams{Name: name, LockID: lockID})
+	require.NoError(t, err)
+	require.True(t, result.Released)
+}
+
+// TestHierarchyLock_IntentionModes tests that hierarchy locks on a subtree conflict with locks on its ancestors through intention modes
+func TestHierarchyLock_IntentionModes(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	root := testLockName(t)
+	tenant := root + "/tenant/a"
+
+	// 1. 같은 tenant 아래의 서로 다른 리소스는 동시에 X 가능 (조상에는 IX)
+	require.True(t, tryHierarchyLock(t, client, tenant+"/invoice/1", "worker_1", HierarchyModeExclusive).Acquired)
+	require.True(t, tryHierarchyLock(t, client, tenant+"/invoice/2", "worker_2", HierarchyModeExclusive).Acquired)
+	assert.False(t, tryHierarchyLock(t, client, tenant+"/invoice/1", "worker_3", HierarchyModeShared).Acquired)
+
+	// 2. 하위 리소스가 잠겨 있으면 tenant 전체의 X, S는 실패
+	result := tryHierarchyLock(t, client, tenant, "admin", HierarchyModeExclusive)
+	assert.False(t, result.Acquired)
+	assert.Equal(t, tenant, result.Conflict.Name)
+	assert.Equal(t, HierarchyModeIntentionExclusive, result.Conflict.Mode)
+	assert.False(t, tryHierarchyLock(t, client, tenant, "reader", HierarchyModeShared).Acquired)
+
+	// IX끼리는 호환되므로 다른 하위 리소스의 잠금은 가능
+	assert.True(t, tryHierarchyLock(t, client, tenant, "planner", HierarchyModeIntentionExclusive).Acquired)
+	hierarchyUnlock(t, client, tenant, "planner")
+
+	// 3. 하위 잠금이 모두 풀리면 tenant 전체를 X로 잠글 수 있음
+	hierarchyUnlock(t, client, tenant+"/invoice/1", "worker_1")
+	hierarchyUnlock(t, client, tenant+"/invoice/2", "worker_2")
+	require.True(t, tryHierarchyLock(t, client, tenant, "admin", HierarchyModeExclusive).Acquired)
+
+	// 4. tenant가 X로 잠겨 있으면 하위 리소스는 S도 실패, 다른 tenant는 영향 없음
+	assert.False(t, tryHierarchyLock(t, client, tenant+"/invoice/1", "worker_1", HierarchyModeShared).Acquired)
+	assert.True(t, tryHierarchyLock(t, client, root+"/tenant/b/invoice/1", "worker_1", HierarchyModeExclusive).Acquired)
+
+	// 5. S는 하위 리소스의 S(IS)와 호환되지만 하위 리소스의 X(IX)와는 충돌
+	hierarchyUnlock(t, client, tenant, "admin")
+	require.True(t, tryHierarchyLock(t, client, tenant, "reader", HierarchyModeShared).Acquired)
+	assert.True(t, tryHierarchyLock(t, client, tenant+"/invoice/1", "reader_2", HierarchyModeShared).Acquired)
+	assert.False(t, tryHierarchyLock(t, client, tenant+"/invoice/2", "worker_2", HierarchyModeExclusive).Acquired)
+
+	unlockResult, err := client.HierarchyUnlock(ctx, HierarchyUnlockParams{Name: tenant, LockID: "reader"})
+	require.NoError(t, err)
+	assert.True(t, unlockResult.Released)
+	unlockResult, err = client.HierarchyUnlock(ctx, HierarchyUnlockParams{Name: tenant, LockID: "reader"})
+	require.NoError(t, err)
+	assert.False(t, unlockResult.Released)
+}
+
+// TestHierarchyLock_RejectsEmptySegments tests that names with an empty path segment are rejected before anything is locked
+func TestHierarchyLock_RejectsEmptySegments(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryLockClient(MemoryLockClientOptions{})
+
+	for _, name := range []string{"", "/tenant", "tenant/", "tenant//invoice"} {
+		_, err := client.TryHierarchyLock(ctx, TryHierarchyLockParams{
+			Name: name, LockID: "worker", Mode: HierarchyModeExclusive, TTLSeconds: 60,
+		})
+		assert.ErrorContains(t, err, "pglock: hierarchy lock name", "name %q", name)
+	}
+
+	// 거부된 이름은 아무것도 잠그지 않음
+	assert.True(t, tryHierarchyLock(t, client, "tenant", "admin", HierarchyModeExclusive).Acquired)
+}
@@ -0,0 +1,167 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// LockInfo describes the live holders of a lock.
+type LockInfo struct {
+	Name           string            // Lock Name
+	XLockID        string            // LockID of the exclusive holder ("" if no live XLock)
+	XExpiresAt     time.Time         // Expiration time of the exclusive lock (zero if no live XLock)
+	XSessionID     string            // Session the exclusive lock is bound to ("" if none)
+	XOwner         *OwnerInfo        // Holder details of the exclusive lock (nil if no live XLock or none were recorded)
+	SharedLocks    []SharedLockEntry // Live shared lock entries
+	MaxSharedLocks int               // Maximum number of shared permits stored for the lock (-1 for unlimited)
+}
+
+type DescribeLockParams struct {
+	Name string // Lock Name: unique identifier for the lock
+}
+
+type DescribeLockResult struct {
+	Lock  LockInfo // Current state of the lock
+	Found bool     // Whether a row exists for the lock name
+}
+
+// DescribeLock returns the current holders of a lock. Expired holders are omitted.
+func (c *lockClient) DescribeLock(ctx context.Context, params DescribeLockParams) (DescribeLockResult, error) {
+	tableName := c.options.LockTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE name = $1;
+	`, lockRowColumns, tableName)
+
+	row, err := scanLockRow(c.db.QueryRowContext(ctx, selectQuery, params.Name))
+	if err == sql.ErrNoRows {
+		return DescribeLockResult{Found: false}, nil
+	}
+	if err != nil {
+		return DescribeLockResult{}, err
+	}
+
+	lock, err := c.liveLockInfo(ctx, c.db, params.Name, row, time.Now())
+	if err != nil {
+		return DescribeLockResult{}, err
+	}
+
+	return DescribeLockResult{Lock: lock, Found: true}, nil
+}
+
+// liveLockInfo returns the holders of a lock row that have not expired before now
+// and whose session is still alive.
+func (c *lockClient) liveLockInfo(ctx context.Context, queryer rowQueryer, name string, row lockRow, now time.Time) (LockInfo, error) {
+	if err := c.expireDeadSessionHolders(ctx, queryer, &row, now); err != nil {
+		return LockInfo{}, err
+	}
+
+	return row.lockInfo(name, now), nil
+}
+
+type ListLocksParams struct {
+	Prefix      string // [optional] Only locks whose name starts with Prefix
+	IncludeFree bool   // [optional] Include locks without any live holder
+	Limit       int    // [optional] Maximum number of locks to return (default value: 100)
+}
+
+type ListLocksResult struct {
+	Locks []LockInfo // Locks ordered by name
+}
+
+// ListLocks returns the locks in the lock table, ordered by name.
+// By default only locks with at least one live holder are returned.
+func (c *lockClient) ListLocks(ctx context.Context, params ListLocksParams) (ListLocksResult, error) {
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	now := time.Now()
+	locks := []LockInfo{}
+	after := sql.NullString{}
+	for len(locks) < params.Limit {
+		// 1. TTL 기준으로 보유자가 있는 행만 필요한 만큼 조회
+		limit := params.Limit - len(locks)
+		names, rows, err := c.selectLockRows(ctx, params, after, limit, now)
+		if err != nil {
+			return ListLocksResult{}, err
+		}
+
+		// 2. 종료된 세션에 묶인 보유자는 SQL로 거를 수 없으므로 조회 후 필터링
+		for i, row := range rows {
+			lock, err := c.liveLockInfo(ctx, c.db, names[i], row, now)
+			if err != nil {
+				return ListLocksResult{}, err
+			}
+			if !params.IncludeFree && lock.XLockID == "" && len(lock.SharedLocks) == 0 {
+				continue
+			}
+
+			locks = append(locks, lock)
+		}
+
+		// 3. 걸러진 행만큼 다음 행부터 다시 조회
+		if len(rows) < limit {
+			break
+		}
+		after = sql.NullString{String: names[len(names)-1], Valid: true}
+	}
+
+	return ListLocksResult{Locks: locks}, nil
+}
+
+// selectLockRows returns up to limit rows of ListLocks ordered by name, starting after the given name.
+// Unless IncludeFree is set, only rows with a holder that has not expired at now are returned.
+func (c *lockClient) selectLockRows(
+	ctx context.Context,
+	params ListLocksParams,
+	after sql.NullString,
+	limit int,
+	now time.Time,
+) ([]string, []lockRow, error) {
+	selectQuery := fmt.Sprintf(`
+		SELECT name, %s
+		FROM %s
+		WHERE starts_with(name, $1)
+			AND ($2::text IS NULL OR name > $2::text)
+			AND (
+				$3::boolean
+				OR (xlock_id IS NOT NULL AND x_expires_at > $4)
+				OR EXISTS (
+					SELECT 1
+					FROM jsonb_array_elements(shared_locks) AS entry
+					WHERE (entry->>'expires_at')::timestamptz > $4
+				)
+			)
+		ORDER BY name ASC
+		LIMIT $5;
+	`, lockRowColumns, c.options.LockTableName)
+
+	rows, err := c.db.QueryContext(ctx, selectQuery, params.Prefix, after, params.IncludeFree, now, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	lockRows := []lockRow{}
+	for rows.Next() {
+		var name string
+		row, err := scanLockRow(rows, &name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		names = append(names, name)
+		lockRows = append(lockRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return names, lockRows, nil
+}
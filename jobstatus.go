@@ -0,0 +1,130 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// The job status table keeps the last run of every scheduled job (see the scheduler package),
+// so that replicas can tell whether a tick has already run and how many ticks were missed.
+
+// JobState is the state of the last run of a job
+type JobState string
+
+const (
+	JobStateRunning   JobState = "running"   // The run has started and not finished (or its replica died)
+	JobStateSucceeded JobState = "succeeded" // The run returned no error
+	JobStateFailed    JobState = "failed"    // The run returned an error
+)
+
+func (c *lockClient) createJobStatusTable(ctx context.Context) error {
+	tableName := c.options.JobStatusTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			name TEXT PRIMARY KEY,
+			scheduled_at TIMESTAMPTZ NOT NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			finished_at TIMESTAMPTZ,
+			run_by TEXT NOT NULL,
+			state TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			missed_runs BIGINT NOT NULL DEFAULT 0
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+
+	return err
+}
+
+// JobStatus is the last run of a scheduled job
+type JobStatus struct {
+	Name        string    // Job name
+	ScheduledAt time.Time // Tick the run was scheduled for
+	StartedAt   time.Time // Time the run started
+	FinishedAt  time.Time // Time the run finished (zero while running)
+	RunBy       string    // LockID of the replica that ran the job
+	State       JobState  // State of the run
+	Error       string    // Error message of the run, if it failed
+	MissedRuns  int64     // Total number of ticks that were not run by any replica
+}
+
+type SaveJobStatusParams struct {
+	Status JobStatus // Status to record; replaces the previous status of the job unless that one is for a later tick
+}
+
+type SaveJobStatusResult struct {
+	Saved bool // Whether the status was recorded (false if a later tick was already recorded)
+}
+
+type GetJobStatusParams struct {
+	Name string // Job name
+}
+
+type GetJobStatusResult struct {
+	Status JobStatus // Last run of the job
+	Found  bool      // Whether the job has run before
+}
+
+// SaveJobStatus records the last run of a job. A status for an earlier tick than the recorded one is ignored,
+// so that a replica that lost the job lock cannot overwrite the run of the replica that took it over.
+func (c *lockClient) SaveJobStatus(ctx context.Context, params SaveJobStatusParams) (SaveJobStatusResult, error) {
+	tableName := c.options.JobStatusTableName
+	status := params.Status
+
+	upsertQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, scheduled_at, started_at, finished_at, run_by, state, error, missed_runs)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (name) DO UPDATE
+		SET scheduled_at = EXCLUDED.scheduled_at, started_at = EXCLUDED.started_at, finished_at = EXCLUDED.finished_at,
+			run_by = EXCLUDED.run_by, state = EXCLUDED.state, error = EXCLUDED.error, missed_runs = EXCLUDED.missed_runs
+		WHERE %s.scheduled_at <= EXCLUDED.scheduled_at;
+	`, tableName, tableName)
+
+	finishedAt := sql.NullTime{Time: status.FinishedAt, Valid: !status.FinishedAt.IsZero()}
+	result, err := c.db.ExecContext(
+		ctx, upsertQuery,
+		status.Name, status.ScheduledAt, status.StartedAt, finishedAt, status.RunBy, status.State, status.Error, status.MissedRuns,
+	)
+	if err != nil {
+		return SaveJobStatusResult{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return SaveJobStatusResult{}, err
+	}
+
+	return SaveJobStatusResult{Saved: rowsAffected > 0}, nil
+}
+
+// GetJobStatus returns the last run of a job.
+func (c *lockClient) GetJobStatus(ctx context.Context, params GetJobStatusParams) (GetJobStatusResult, error) {
+	tableName := c.options.JobStatusTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT scheduled_at, started_at, finished_at, run_by, state, error, missed_runs
+		FROM %s
+		WHERE name = $1;
+	`, tableName)
+
+	status := JobStatus{Name: params.Name}
+	var finishedAt sql.NullTime
+	err := c.db.QueryRowContext(ctx, selectQuery, params.Name).Scan(
+		&status.ScheduledAt, &status.StartedAt, &finishedAt, &status.RunBy, &status.State, &status.Error, &status.MissedRuns,
+	)
+	if err == sql.ErrNoRows {
+		return GetJobStatusResult{Found: false}, nil
+	}
+	if err != nil {
+		return GetJobStatusResult{}, err
+	}
+	if finishedAt.Valid {
+		status.FinishedAt = finishedAt.Time
+	}
+
+	return GetJobStatusResult{Status: status, Found: true}, nil
+}
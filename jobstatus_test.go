@@ -0,0 +1,59 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJobStatus_SaveAndGet tests that the status of a job run is saved and replaced by later runs
+func TestJobStatus_SaveAndGet(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	statuses := client.(JobStatusStore)
+	name := testLockName(t)
+
+	// 1. 실행 기록이 없으면 찾을 수 없음
+	getResult, err := statuses.GetJobStatus(ctx, GetJobStatusParams{Name: name})
+	require.NoError(t, err)
+	assert.False(t, getResult.Found)
+
+	// 2. 실행 중 상태 기록 후 완료 상태로 교체
+	scheduledAt := time.Now().Truncate(time.Minute)
+	status := JobStatus{
+		Name:        name,
+		ScheduledAt: scheduledAt,
+		StartedAt:   scheduledAt.Add(time.Second),
+		RunBy:       "replica_1",
+		State:       JobStateRunning,
+		MissedRuns:  3,
+	}
+	_, err = statuses.SaveJobStatus(ctx, SaveJobStatusParams{Status: status})
+	require.NoError(t, err)
+
+	getResult, err = statuses.GetJobStatus(ctx, GetJobStatusParams{Name: name})
+	require.NoError(t, err)
+	require.True(t, getResult.Found)
+	assert.Equal(t, JobStateRunning, getResult.Status.State)
+	assert.True(t, getResult.Status.FinishedAt.IsZero())
+
+	status.FinishedAt = scheduledAt.Add(2 * time.Second)
+	status.State = JobStateFailed
+	status.Error = "disk full"
+	_, err = statuses.SaveJobStatus(ctx, SaveJobStatusParams{Status: status})
+	require.NoError(t, err)
+
+	getResult, err = statuses.GetJobStatus(ctx, GetJobStatusParams{Name: name})
+	require.NoError(t, err)
+	assert.Equal(t, name, getResult.Status.Name)
+	assert.True(t, scheduledAt.Equal(getResult.Status.ScheduledAt))
+	assert.True(t, status.FinishedAt.Equal(getResult.Status.FinishedAt))
+	assert.Equal(t, "replica_1", getResult.Status.RunBy)
+	assert.Equal(t, JobStateFailed, getResult.Status.State)
+	assert.Equal(t, "disk full", getResult.Status.Error)
+	assert.Equal(t, int64(3), getResult.Status.MissedRuns)
+}
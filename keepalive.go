@@ -0,0 +1,102 @@
+package pglock
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type KeepAliveParams struct {
+	Name             string        // Lock Name: unique identifier for the lock
+	LockID           string        // Lock LockID: identifier for the entity holding the lock
+	TTLSeconds       int           // Time-To-Live set on every refresh
+	ExpiresAt        time.Time     // Current expiration time of the lock (default value: now + TTL)
+	IntervalDuration time.Duration // Refresh interval (default value: TTL / 3)
+}
+
+// KeepAlive refreshes a held lock in the background until ctx is done or the lock is lost.
+// The returned channel receives ErrLockLost (wrapping the last refresh error, if any) when
+// the lock could not be kept, and is closed when refreshing stops for any reason.
+// Failed refreshes are retried until the lock's known expiration time passes.
+func KeepAlive(ctx context.Context, client LockClient, params KeepAliveParams) <-chan error {
+	lease := renewedLease{
+		TTLSeconds:       params.TTLSeconds,
+		ExpiresAt:        params.ExpiresAt,
+		IntervalDuration: params.IntervalDuration,
+		ErrLost:          ErrLockLost,
+	}
+
+	return lease.keep(ctx, func(ctx context.Context) (time.Time, bool, error) {
+		result, err := client.Refresh(ctx, RefreshParams{
+			Name:       params.Name,
+			LockID:     params.LockID,
+			TTLSeconds: params.TTLSeconds,
+		})
+
+		return result.ExpiresAt, result.Refreshed, err
+	})
+}
+
+// renewedLease describes a lease (a lock or a session) kept alive by periodic renewals.
+type renewedLease struct {
+	TTLSeconds       int           // Time-To-Live set on every renewal
+	ExpiresAt        time.Time     // Current expiration time of the lease (default value: now + TTL)
+	IntervalDuration time.Duration // Renewal interval (default value: TTL / 3)
+	ErrLost          error         // Error reported when the lease could not be kept
+}
+
+// keep calls renew in the background until ctx is done or the lease is lost. renew returns the new
+// expiration time and whether the lease was still held. The returned channel receives ErrLost (wrapping
+// the last renewal error, if any) when the lease is lost, and is closed when renewing stops.
+func (l renewedLease) keep(ctx context.Context, renew func(ctx context.Context) (time.Time, bool, error)) <-chan error {
+	ttl := time.Duration(l.TTLSeconds) * time.Second
+	if l.IntervalDuration <= 0 {
+		l.IntervalDuration = ttl / 3
+	}
+	// TTL이 너무 짧으면 타이트 루프 방지
+	if l.IntervalDuration <= 0 {
+		l.IntervalDuration = DefaultRetryInterval
+	}
+	if l.ExpiresAt.IsZero() {
+		l.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	lost := make(chan error, 1)
+
+	go func() {
+		defer close(lost)
+
+		expiresAt := l.ExpiresAt
+		ticker := time.NewTicker(l.IntervalDuration)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			newExpiresAt, renewed, err := renew(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				// 만료 전까지는 일시적인 오류로 보고 재시도
+				if time.Now().Before(expiresAt) {
+					continue
+				}
+				lost <- fmt.Errorf("%w: %w", l.ErrLost, err)
+				return
+			}
+			if !renewed {
+				lost <- l.ErrLost
+				return
+			}
+
+			expiresAt = newExpiresAt
+		}
+	}()
+
+	return lost
+}
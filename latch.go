@@ -0,0 +1,275 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A countdown latch starts at a count and is counted down by any process; waiters are
+// released once it reaches zero. It cannot be counted up again, so a latch that should be
+// reused (e.g. once per release) needs a new name each time.
+// A count down may carry an EventID: the IDs counted so far are kept with the latch, so a
+// retried count down (e.g. after a timeout whose update did commit) is not counted twice.
+
+// errInvalidLatchCount is returned when a latch is created with a negative count
+var errInvalidLatchCount = errors.New("pglock: latch count must not be negative")
+
+func (c *lockClient) createLatchTable(ctx context.Context) error {
+	tableName := c.options.LatchTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			name TEXT PRIMARY KEY,
+			count INT NOT NULL,
+			remaining INT NOT NULL,
+			counted_events TEXT[] NOT NULL DEFAULT '{}'
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+
+	return err
+}
+
+// LatchInfo is the state of a countdown latch
+type LatchInfo struct {
+	Name      string // Latch Name
+	Count     int    // Count the latch was created with
+	Remaining int    // Count downs left before the latch opens
+}
+
+type CreateLatchParams struct {
+	Name  string // Latch Name
+	Count int    // Number of count downs before the latch opens
+}
+
+type CreateLatchResult struct {
+	Latch   LatchInfo // State of the latch
+	Created bool      // Whether the latch was created (false if it already existed and was left as is)
+}
+
+type CountDownParams struct {
+	Name    string // Latch Name
+	EventID string // [optional] Identifier of the event being counted. A count down repeated with the same EventID is counted once
+}
+
+type CountDownResult struct {
+	Remaining int  // Count downs left after this one
+	Found     bool // Whether the latch exists
+	Duplicate bool // Whether EventID had already been counted, leaving the count as is
+}
+
+type DescribeLatchParams struct {
+	Name string // Latch Name
+}
+
+type DescribeLatchResult struct {
+	Latch LatchInfo // State of the latch
+	Found bool      // Whether the latch exists
+}
+
+// CreateLatch creates a latch with Count, unless a latch with the same name already exists.
+func (c *lockClient) CreateLatch(ctx context.Context, params CreateLatchParams) (CreateLatchResult, error) {
+	if params.Count < 0 {
+		return CreateLatchResult{}, errInvalidLatchCount
+	}
+
+	tableName := c.options.LatchTableName
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, count, remaining)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (name) DO NOTHING;
+	`, tableName)
+	result, err := c.db.ExecContext(ctx, insertQuery, params.Name, params.Count)
+	if err != nil {
+		return CreateLatchResult{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return CreateLatchResult{}, err
+	}
+	if rowsAffected > 0 {
+		return CreateLatchResult{
+			Latch:   LatchInfo{Name: params.Name, Count: params.Count, Remaining: params.Count},
+			Created: true,
+		}, nil
+	}
+
+	// 이미 있으면 현재 상태 반환
+	describeResult, err := c.DescribeLatch(ctx, DescribeLatchParams{Name: params.Name})
+	if err != nil {
+		return CreateLatchResult{}, err
+	}
+
+	return CreateLatchResult{Latch: describeResult.Latch, Created: false}, nil
+}
+
+// CountDown decrements the count of a latch. A latch that is already open stays at zero.
+// A count down with an EventID that was already counted leaves the count as is.
+func (c *lockClient) CountDown(ctx context.Context, params CountDownParams) (CountDownResult, error) {
+	tableName := c.options.LatchTableName
+
+	// 열린 뒤의 이벤트는 세지 않으므로 기록하지 않음 (기록되는 ID는 Count개 이하)
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET remaining = GREATEST(remaining - 1, 0),
+			counted_events = CASE
+				WHEN $2::text = '' OR remaining = 0 THEN counted_events
+				ELSE array_append(counted_events, $2::text)
+			END
+		WHERE name = $1 AND ($2::text = '' OR NOT $2::text = ANY(counted_events))
+		RETURNING remaining;
+	`, tableName)
+
+	var remaining int
+	err := c.db.QueryRowContext(ctx, updateQuery, params.Name, params.EventID).Scan(&remaining)
+	if err == sql.ErrNoRows {
+		if params.EventID == "" {
+			return CountDownResult{Found: false}, nil
+		}
+
+		// latch가 없거나 이미 센 이벤트
+		describeResult, err := c.DescribeLatch(ctx, DescribeLatchParams{Name: params.Name})
+		if err != nil || !describeResult.Found {
+			return CountDownResult{Found: false}, err
+		}
+
+		return CountDownResult{Remaining: describeResult.Latch.Remaining, Found: true, Duplicate: true}, nil
+	}
+	if err != nil {
+		return CountDownResult{}, err
+	}
+
+	return CountDownResult{Remaining: remaining, Found: true}, nil
+}
+
+// DescribeLatch returns the state of a latch.
+func (c *lockClient) DescribeLatch(ctx context.Context, params DescribeLatchParams) (DescribeLatchResult, error) {
+	tableName := c.options.LatchTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT count, remaining
+		FROM %s
+		WHERE name = $1;
+	`, tableName)
+
+	latch := LatchInfo{Name: params.Name}
+	err := c.db.QueryRowContext(ctx, selectQuery, params.Name).Scan(&latch.Count, &latch.Remaining)
+	if err == sql.ErrNoRows {
+		return DescribeLatchResult{Found: false}, nil
+	}
+	if err != nil {
+		return DescribeLatchResult{}, err
+	}
+
+	return DescribeLatchResult{Latch: latch, Found: true}, nil
+}
+
+type CountDownLatchOptions struct {
+	Name         string        // [required] Latch name shared by the processes
+	Count        int           // [required for Create] Number of count downs before the latch opens
+	PollInterval time.Duration // [optional] Interval between checks in Await. default: 100ms
+}
+
+func (options *CountDownLatchOptions) SetDefaults() {
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultRetryInterval
+	}
+}
+
+// CountDownLatch lets processes wait until a number of events (e.g. migrations) have happened elsewhere.
+type CountDownLatch struct {
+	client  LockClient
+	options CountDownLatchOptions
+}
+
+// NewCountDownLatch returns a CountDownLatch that coordinates through client, which must implement LatchStore.
+func NewCountDownLatch(client LockClient, options CountDownLatchOptions) *CountDownLatch {
+	options.SetDefaults()
+
+	return &CountDownLatch{client: client, options: options}
+}
+
+// Create creates the latch with Count. It is a no-op if the latch already exists,
+// so every process may call it at startup.
+func (l *CountDownLatch) Create(ctx context.Context) error {
+	store, err := storeOf[LatchStore](l.client)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.CreateLatch(ctx, CreateLatchParams{Name: l.options.Name, Count: l.options.Count})
+	return err
+}
+
+// CountDown decrements the count and returns the count downs left.
+// A CountDown that fails after reaching the store may or may not have been counted;
+// use CountDownEvent to retry it safely.
+func (l *CountDownLatch) CountDown(ctx context.Context) (int, error) {
+	return l.CountDownEvent(ctx, "")
+}
+
+// CountDownEvent decrements the count for the event identified by eventID and returns the count downs left.
+// Repeating it with the same eventID (e.g. retrying after an error) counts the event once.
+func (l *CountDownLatch) CountDownEvent(ctx context.Context, eventID string) (int, error) {
+	store, err := storeOf[LatchStore](l.client)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := store.CountDown(ctx, CountDownParams{Name: l.options.Name, EventID: eventID})
+	if err != nil {
+		return 0, err
+	}
+	if !result.Found {
+		return 0, fmt.Errorf("pglock: latch %q does not exist", l.options.Name)
+	}
+
+	return result.Remaining, nil
+}
+
+// Await blocks until the count reaches zero or ctx is done.
+// A latch that does not exist yet is waited for, since it may be created by another process.
+func (l *CountDownLatch) Await(ctx context.Context) error {
+	store, err := storeOf[LatchStore](l.client)
+	if err != nil {
+		return err
+	}
+
+	for {
+		result, err := store.DescribeLatch(ctx, DescribeLatchParams{Name: l.options.Name})
+		if err != nil {
+			return err
+		}
+		if result.Found && result.Latch.Remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.options.PollInterval):
+			// 재확인
+		}
+	}
+}
+
+// Inspect returns the current state of the latch, and whether it exists.
+func (l *CountDownLatch) Inspect(ctx context.Context) (LatchInfo, bool, error) {
+	store, err := storeOf[LatchStore](l.client)
+	if err != nil {
+		return LatchInfo{}, false, err
+	}
+
+	result, err := store.DescribeLatch(ctx, DescribeLatchParams{Name: l.options.Name})
+	if err != nil {
+		return LatchInfo{}, false, err
+	}
+
+	return result.Latch, result.Found, nil
+}
@@ -0,0 +1,102 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLatch_CountDown tests that a latch is created once, counts down at most once per EventID and releases its waiters at zero
+func TestLatch_CountDown(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	latches := client.(LatchStore)
+	name := testLockName(t)
+
+	// 1. 없는 latch는 찾을 수 없음
+	describeResult, err := latches.DescribeLatch(ctx, DescribeLatchParams{Name: name})
+	require.NoError(t, err)
+	assert.False(t, describeResult.Found)
+
+	countDownResult, err := latches.CountDown(ctx, CountDownParams{Name: name})
+	require.NoError(t, err)
+	assert.False(t, countDownResult.Found)
+
+	// 2. 생성은 한 번만 적용되고, 이후 호출은 기존 상태를 반환
+	createResult, err := latches.CreateLatch(ctx, CreateLatchParams{Name: name, Count: 2})
+	require.NoError(t, err)
+	assert.True(t, createResult.Created)
+	assert.Equal(t, LatchInfo{Name: name, Count: 2, Remaining: 2}, createResult.Latch)
+
+	countDownResult, err = latches.CountDown(ctx, CountDownParams{Name: name})
+	require.NoError(t, err)
+	assert.True(t, countDownResult.Found)
+	assert.Equal(t, 1, countDownResult.Remaining)
+
+	createResult, err = latches.CreateLatch(ctx, CreateLatchParams{Name: name, Count: 5})
+	require.NoError(t, err)
+	assert.False(t, createResult.Created)
+	assert.Equal(t, LatchInfo{Name: name, Count: 2, Remaining: 1}, createResult.Latch)
+
+	// 3. Await는 0이 될 때까지 대기하고, 0 이하로는 내려가지 않음
+	latch := NewCountDownLatch(client, CountDownLatchOptions{Name: name, PollInterval: 10 * time.Millisecond})
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, latch.Await(timeoutCtx), context.DeadlineExceeded)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- latch.Await(ctx)
+	}()
+
+	remaining, err := latch.CountDown(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("latch was not released")
+	}
+
+	remaining, err = latch.CountDown(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+
+	info, found, err := latch.Inspect(ctx)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, LatchInfo{Name: name, Count: 2, Remaining: 0}, info)
+
+	// 4. 같은 EventID로 반복한 CountDown은 한 번만 셈
+	eventsLatch := NewCountDownLatch(client, CountDownLatchOptions{Name: name + "/events", Count: 2})
+	require.NoError(t, eventsLatch.Create(ctx))
+
+	remaining, err = eventsLatch.CountDownEvent(ctx, "migration-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+
+	countDownResult, err = latches.CountDown(ctx, CountDownParams{Name: name + "/events", EventID: "migration-1"})
+	require.NoError(t, err)
+	assert.True(t, countDownResult.Found)
+	assert.True(t, countDownResult.Duplicate)
+	assert.Equal(t, 1, countDownResult.Remaining)
+
+	countDownResult, err = latches.CountDown(ctx, CountDownParams{Name: name + "/events", EventID: "migration-2"})
+	require.NoError(t, err)
+	assert.False(t, countDownResult.Duplicate)
+	assert.Equal(t, 0, countDownResult.Remaining)
+
+	countDownResult, err = latches.CountDown(ctx, CountDownParams{Name: name + "/missing", EventID: "migration-1"})
+	require.NoError(t, err)
+	assert.False(t, countDownResult.Found)
+
+	_, err = latches.CreateLatch(ctx, CreateLatchParams{Name: testLockName(t) + "/invalid", Count: -1})
+	assert.Error(t, err)
+}
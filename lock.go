@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 )
 
@@ -22,7 +23,10 @@ func (c *lockClient) createLockTable(ctx context.Context) error {
 			xlock_id TEXT,
 			x_expires_at TIMESTAMPTZ,
 			shared_locks JSONB DEFAULT '[]'::jsonb,
-			max_shared_locks INT DEFAULT -1
+			max_shared_locks INT DEFAULT -1,
+			x_session_id TEXT,
+			x_owner JSONB,
+			value BYTEA
 		);
 	`, tableName)
 
@@ -31,6 +35,17 @@ func (c *lockClient) createLockTable(ctx context.Context) error {
 		return err
 	}
 
+	// 세션/보유자 정보/값 기능 이전에 만들어진 테이블에 컬럼 추가
+	alterTableSQL := fmt.Sprintf(`
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS x_session_id TEXT, ADD COLUMN IF NOT EXISTS x_owner JSONB,
+			ADD COLUMN IF NOT EXISTS value BYTEA;
+	`, tableName)
+
+	_, err = c.db.ExecContext(ctx, alterTableSQL)
+	if err != nil {
+		return err
+	}
+
 	// GIN 인덱스 생성 (JSONB 검색 최적화)
 	createIndexSQL := fmt.Sprintf(`
 		CREATE INDEX IF NOT EXISTS idx_%s_shared ON %s USING GIN (shared_locks);
@@ -42,9 +57,12 @@ func (c *lockClient) createLockTable(ctx context.Context) error {
 }
 
 type TryXLockParams struct {
-	Name       string // Lock Name: unique identifier for the lock
-	LockID     string // Lock LockID: identifier for the entity requesting the lock
-	TTLSeconds int    // Time-To-Live: duration in seconds for the lock
+	Name       string          // Lock Name: unique identifier for the lock
+	LockID     string          // Lock LockID: identifier for the entity requesting the lock
+	TTLSeconds int             // Time-To-Live: duration in seconds for the lock
+	SessionID  string          // [optional] Session the lock is bound to: it is released when the session ends, even before the TTL
+	Metadata   json.RawMessage // [optional] JSON recorded with the audit events of this acquisition
+	Owner      OwnerInfo       // [optional] Holder details returned by DescribeLock and ListLocks. Empty fields default to this process, the acquisition time and Metadata
 }
 
 type TryXLockResult struct {
@@ -53,101 +71,119 @@ type TryXLockResult struct {
 }
 
 // TryXLock attempts to acquire a distributed lock.
+// The TTL is adjusted by the lock policy for the name, if any.
 // Returns the expiration time, whether the lock was acquired, and any error.
 func (c *lockClient) TryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
+	startedAt := time.Now()
+
+	result, err := c.tracedTryXLock(ctx, params)
+
+	c.observe(LockEvent{
+		Operation: OperationTryXLock,
+		Name:      params.Name,
+		Mode:      LockModeExclusive,
+		Outcome:   outcomeOf(err, result.Acquired, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+// tracedTryXLock runs tryXLock inside its own span.
+func (c *lockClient) tracedTryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
+	ctx, span := c.startSpan(ctx, "pglock.TryXLock", LockModeExclusive, params.Name, params.LockID)
+
+	result, err := c.tryXLock(ctx, params)
+
+	span.SetAttributes(AttributeAcquired.Bool(result.Acquired))
+	endSpan(span, err)
+
+	return result, err
+}
+
+func (c *lockClient) tryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
 	transaction, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return TryXLockResult{}, err
 	}
 
-	tableName := c.options.LockTableName
-
-	xExpiresAtFromParams := sql.NullTime{
-		Time:  time.Now().Add(time.Duration(params.TTLSeconds) * time.Second),
-		Valid: true,
+	// 0. gate 잠금 후 트랜잭션 범위 보유자 확인
+	txHolders, err := c.lockGate(ctx, transaction, params.Name, 0)
+	if err != nil {
+		_ = transaction.Rollback()
+		return TryXLockResult{}, err
+	}
+	if txHolders.exclusive > 0 || txHolders.shared > 0 {
+		_ = transaction.Rollback()
+		reason := conflictSharedHolders
+		if txHolders.exclusive > 0 {
+			reason = conflictExclusiveHolder
+		}
+		c.logConflict(ctx, LockModeExclusive, params.Name, params.LockID, reason, slog.String("holder", "transaction"))
+		return TryXLockResult{Acquired: false}, nil
 	}
 
-	// 1. lock 행 생성 (없으면)
-	ensureQuery := fmt.Sprintf(`
-		INSERT INTO %s (name, xlock_id, x_expires_at, shared_locks, max_shared_locks)
-		VALUES ($1, $2, $3, '[]'::jsonb, -1)
-		ON CONFLICT (name) DO NOTHING
-		RETURNING name;
-	`, tableName)
-	result, err := transaction.ExecContext(ctx, ensureQuery, params.Name, params.LockID, xExpiresAtFromParams)
+	// 정책 조회 및 TTL 적용
+	policy, _, err := c.findLockPolicy(ctx, transaction, params.Name)
 	if err != nil {
 		_ = transaction.Rollback()
 		return TryXLockResult{}, err
 	}
+	params.TTLSeconds = policy.resolveTTL(params.TTLSeconds)
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	// 세션에 묶인 요청이면 세션이 살아 있어야 함
+	if err := c.checkSession(ctx, transaction, params.SessionID); err != nil {
 		_ = transaction.Rollback()
 		return TryXLockResult{}, err
 	}
 
-	if rowsAffected > 0 {
-		// 새로 생성되어 바로 획득 성공
-		if err := transaction.Commit(); err != nil {
-			return TryXLockResult{}, err
-		}
+	now := time.Now()
 
-		return TryXLockResult{ExpiresAt: xExpiresAtFromParams.Time, Acquired: true}, nil
+	// 1. lock 행 생성 (없으면) - 빈 행에는 보유자가 없으므로 항상 획득 가능
+	created, _ := acquireXLock(lockRow{maxSharedLocks: -1}, params, now)
+	inserted, err := c.insertLockRow(ctx, transaction, params.Name, created.row)
+	if err != nil {
+		_ = transaction.Rollback()
+		return TryXLockResult{}, err
+	}
+	if inserted {
+		return c.commitXLock(ctx, transaction, params, created)
 	}
 
 	// 2. FOR UPDATE로 행 잠금 및 현재 상태 조회
-	selectQuery := fmt.Sprintf(`
-		SELECT xlock_id, x_expires_at, shared_locks
-		FROM %s
-		WHERE name = $1
-		FOR UPDATE;
-	`, tableName)
-
-	var sharedLocksJSON []byte
-	var xlockID sql.NullString
-	var xExpiresAt sql.NullTime
-
-	err = transaction.QueryRowContext(ctx, selectQuery, params.Name).Scan(
-		&xlockID, &xExpiresAt, &sharedLocksJSON,
-	)
+	row, err := c.selectLockRowForUpdate(ctx, transaction, params.Name)
 	if err != nil {
 		_ = transaction.Rollback()
 		return TryXLockResult{}, err
 	}
 
-	// 3. 기존 XLock 확인
-	if xlockID.Valid && xExpiresAt.Valid && xExpiresAt.Time.After(time.Now()) {
+	// 종료된 세션에 묶인 보유자는 TTL과 무관하게 만료된 것으로 취급
+	if err := c.expireDeadSessionHolders(ctx, transaction, &row, now); err != nil {
 		_ = transaction.Rollback()
-		return TryXLockResult{Acquired: false}, nil
+		return TryXLockResult{}, err
 	}
 
-	// 4. 유효한 SLock 확인
-	var sharedLocks []SharedLockEntry
-	if len(sharedLocksJSON) > 0 {
-		if err := json.Unmarshal(sharedLocksJSON, &sharedLocks); err != nil {
-			_ = transaction.Rollback()
-			return TryXLockResult{}, fmt.Errorf("failed to parse shared_locks: %w", err)
-		}
+	// 3. 기존 보유자 확인 후 XLock 설정
+	change, conflict := acquireXLock(row, params, now)
+	if conflict != nil {
+		_ = transaction.Rollback()
+		c.logConflict(ctx, LockModeExclusive, params.Name, params.LockID, conflict.reason, conflict.attrs...)
+		return TryXLockResult{Acquired: false}, nil
 	}
 
-	for _, lock := range sharedLocks {
-		if lock.ExpiresAt.After(time.Now()) {
-			// 유효한 SLock이 존재
-			_ = transaction.Rollback()
-			return TryXLockResult{Acquired: false}, nil
-		}
+	if err := c.updateLockRow(ctx, transaction, params.Name, change.row); err != nil {
+		_ = transaction.Rollback()
+		return TryXLockResult{}, err
 	}
 
-	// 5. XLock 설정
-	newExpiresAt := time.Now().Add(time.Duration(params.TTLSeconds) * time.Second)
-	updateQuery := fmt.Sprintf(`
-		UPDATE %s
-		SET xlock_id = $1, x_expires_at = $2
-		WHERE name = $3;
-	`, tableName)
+	return c.commitXLock(ctx, transaction, params, change)
+}
 
-	_, err = transaction.ExecContext(ctx, updateQuery, params.LockID, newExpiresAt, params.Name)
-	if err != nil {
+// commitXLock records the audit events of an acquired XLock and commits its transaction.
+func (c *lockClient) commitXLock(ctx context.Context, transaction *sql.Tx, params TryXLockParams, change lockChange) (TryXLockResult, error) {
+	if err := c.recordAuditEvents(ctx, transaction, change.events...); err != nil {
 		_ = transaction.Rollback()
 		return TryXLockResult{}, err
 	}
@@ -156,14 +192,19 @@ func (c *lockClient) TryXLock(ctx context.Context, params TryXLockParams) (TryXL
 		return TryXLockResult{}, err
 	}
 
-	return TryXLockResult{ExpiresAt: newExpiresAt, Acquired: true}, nil
+	c.logAcquired(ctx, LockModeExclusive, params.Name, params.LockID, change.expiresAt)
+
+	return TryXLockResult{ExpiresAt: change.expiresAt, Acquired: true}, nil
 }
 
 type XLockParams struct {
-	Name             string        // Lock Name: unique identifier for the lock
-	LockID           string        // Lock LockID: identifier for the entity requesting the lock
-	TTLSeconds       int           // Time-To-Live: duration in seconds for the lock
-	IntervalDuration time.Duration // Retry interval duration (default value: 100ms)
+	Name             string          // Lock Name: unique identifier for the lock
+	LockID           string          // Lock LockID: identifier for the entity requesting the lock
+	TTLSeconds       int             // Time-To-Live: duration in seconds for the lock
+	IntervalDuration time.Duration   // Retry interval duration (default value: 100ms)
+	SessionID        string          // [optional] Session the lock is bound to: it is released when the session ends, even before the TTL
+	Metadata         json.RawMessage // [optional] JSON recorded with the audit events of this acquisition
+	Owner            OwnerInfo       // [optional] Holder details returned by DescribeLock and ListLocks. Empty fields default to this process, the acquisition time and Metadata
 }
 
 type XLockResult struct {
@@ -172,28 +213,56 @@ type XLockResult struct {
 
 // Lock continuously attempts to acquire a distributed lock until successful.
 func (c *lockClient) XLock(ctx context.Context, params XLockParams) (XLockResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.XLock", LockModeExclusive, params.Name, params.LockID)
+
+	result, attempts, err := c.xLock(ctx, params)
+
+	endWaitSpan(span, attempts, time.Since(startedAt), err == nil, err)
+	c.observe(LockEvent{
+		Operation: OperationXLock,
+		Name:      params.Name,
+		Mode:      LockModeExclusive,
+		Outcome:   outcomeOf(err, true, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  attempts,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+// xLock returns the result of XLock along with the number of attempts made.
+func (c *lockClient) xLock(ctx context.Context, params XLockParams) (XLockResult, int, error) {
 	// IntervalDuration이 0 이하면 기본값 사용 (타이트 루프 방지)
 	if params.IntervalDuration <= 0 {
 		params.IntervalDuration = DefaultRetryInterval
 	}
 
+	attempts := 0
 	for {
-		result, err := c.TryXLock(ctx, TryXLockParams{
+		attempts++
+		c.logAttempt(ctx, LockModeExclusive, params.Name, params.LockID, attempts)
+
+		result, err := c.tracedTryXLock(ctx, TryXLockParams{
 			Name:       params.Name,
 			LockID:     params.LockID,
 			TTLSeconds: params.TTLSeconds,
+			SessionID:  params.SessionID,
+			Metadata:   params.Metadata,
+			Owner:      params.Owner,
 		})
 		if err != nil {
-			return XLockResult{}, err
+			return XLockResult{}, attempts, err
 		}
 		if result.Acquired {
-			return XLockResult{ExpiresAt: result.ExpiresAt}, nil
+			return XLockResult{ExpiresAt: result.ExpiresAt}, attempts, nil
 		}
 
 		// 컨텍스트 취소 확인
 		select {
 		case <-ctx.Done():
-			return XLockResult{}, ctx.Err()
+			return XLockResult{}, attempts, ctx.Err()
 		case <-time.After(params.IntervalDuration):
 			// 재시도
 		}
@@ -211,16 +280,42 @@ type UnlockResult struct {
 
 // SharedLockEntry represents a single shared lock entry in the JSONB array
 type SharedLockEntry struct {
-	LockID    string    `json:"lock_id"`
-	ExpiresAt time.Time `json:"expires_at"`
+	LockID    string     `json:"lock_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	Weight    int        `json:"weight,omitempty"`     // Number of permits held (entries written before weights existed count as 1)
+	SessionID string     `json:"session_id,omitempty"` // Session the lock is bound to ("" if none)
+	Owner     *OwnerInfo `json:"owner,omitempty"`      // Holder details (nil for entries written before owners were recorded)
+}
+
+// PermitWeight returns the number of permits the entry occupies.
+func (e SharedLockEntry) PermitWeight() int {
+	if e.Weight <= 0 {
+		return 1
+	}
+
+	return e.Weight
+}
+
+// totalSharedWeight sums the permits of the given shared lock entries.
+func totalSharedWeight(entries []SharedLockEntry) int {
+	total := 0
+	for _, entry := range entries {
+		total += entry.PermitWeight()
+	}
+
+	return total
 }
 
 // TrySLockParams represents the parameters for acquiring a shared lock (non-blocking)
 type TrySLockParams struct {
-	Name           string // Lock Name: unique identifier for the lock
-	LockID         string // Lock ID: identifier for the entity requesting the lock
-	TTLSeconds     int    // Time-To-Live: duration in seconds for the lock
-	MaxSharedLocks int    // Maximum number of shared locks allowed (-1 for unlimited)
+	Name           string          // Lock Name: unique identifier for the lock
+	LockID         string          // Lock ID: identifier for the entity requesting the lock
+	TTLSeconds     int             // Time-To-Live: duration in seconds for the lock
+	MaxSharedLocks int             // Maximum number of permits allowed (-1 or 0 for unlimited)
+	Weight         int             // Number of permits to take (default value: 1)
+	SessionID      string          // [optional] Session the lock is bound to: it is released when the session ends, even before the TTL
+	Metadata       json.RawMessage // [optional] JSON recorded with the audit events of this acquisition
+	Owner          OwnerInfo       // [optional] Holder details returned by DescribeLock and ListLocks. Empty fields default to this process, the acquisition time and Metadata
 }
 
 // TrySLockResult represents the result of a shared lock acquisition attempt
@@ -231,11 +326,15 @@ type TrySLockResult struct {
 
 // SLockParams represents the parameters for acquiring a shared lock (blocking)
 type SLockParams struct {
-	Name             string        // Lock Name: unique identifier for the lock
-	LockID           string        // Lock ID: identifier for the entity requesting the lock
-	TTLSeconds       int           // Time-To-Live: duration in seconds for the lock
-	MaxSharedLocks   int           // Maximum number of shared locks allowed (-1 for unlimited)
-	IntervalDuration time.Duration // Retry interval duration (default value: 100ms)
+	Name             string          // Lock Name: unique identifier for the lock
+	LockID           string          // Lock ID: identifier for the entity requesting the lock
+	TTLSeconds       int             // Time-To-Live: duration in seconds for the lock
+	MaxSharedLocks   int             // Maximum number of permits allowed (-1 or 0 for unlimited)
+	Weight           int             // Number of permits to take (default value: 1)
+	IntervalDuration time.Duration   // Retry interval duration (default value: 100ms)
+	SessionID        string          // [optional] Session the lock is bound to: it is released when the session ends, even before the TTL
+	Metadata         json.RawMessage // [optional] JSON recorded with the audit events of this acquisition
+	Owner            OwnerInfo       // [optional] Holder details returned by DescribeLock and ListLocks. Empty fields default to this process, the acquisition time and Metadata
 }
 
 // SLockResult represents the result of a shared lock acquisition
@@ -244,151 +343,135 @@ type SLockResult struct {
 }
 
 // TrySLock attempts to acquire a shared lock (non-blocking).
+// The sum of the weights of all live shared locks never exceeds MaxSharedLocks.
+// If a lock policy applies to the name, its MaxSharedLocks and TTL limits take precedence over the params.
 // Returns the expiration time, whether the lock was acquired, and any error.
 func (c *lockClient) TrySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
-	transaction, err := c.db.BeginTx(ctx, nil)
-	if err != nil {
-		return TrySLockResult{}, err
-	}
+	startedAt := time.Now()
 
-	tableName := c.options.LockTableName
+	result, err := c.tracedTrySLock(ctx, params)
 
-	newExpiresAt := time.Now().Add(time.Duration(params.TTLSeconds) * time.Second)
+	c.observe(LockEvent{
+		Operation: OperationTrySLock,
+		Name:      params.Name,
+		Mode:      LockModeShared,
+		Outcome:   outcomeOf(err, result.Acquired, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
 
-	// 1. lock 행 생성 (없으면) - SLock이므로 shared_locks에 초기 엔트리 추가
-	newLockEntry := SharedLockEntry{
-		LockID:    params.LockID,
-		ExpiresAt: newExpiresAt,
-	}
-	initialSharedLocks, err := json.Marshal([]SharedLockEntry{newLockEntry})
-	if err != nil {
-		_ = transaction.Rollback()
-		return TrySLockResult{}, fmt.Errorf("failed to marshal initial shared_locks: %w", err)
+	return result, err
+}
+
+// tracedTrySLock runs trySLock inside its own span.
+func (c *lockClient) tracedTrySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
+	ctx, span := c.startSpan(ctx, "pglock.TrySLock", LockModeShared, params.Name, params.LockID)
+
+	result, err := c.trySLock(ctx, params)
+
+	span.SetAttributes(AttributeAcquired.Bool(result.Acquired))
+	endSpan(span, err)
+
+	return result, err
+}
+
+func (c *lockClient) trySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
+	// Weight가 0 이하면 기본값 1 사용
+	if params.Weight <= 0 {
+		params.Weight = 1
 	}
 
-	ensureQuery := fmt.Sprintf(`
-		INSERT INTO %s (name, xlock_id, x_expires_at, shared_locks, max_shared_locks)
-		VALUES ($1, NULL, NULL, $2::jsonb, $3)
-		ON CONFLICT (name) DO NOTHING
-		RETURNING name;
-	`, tableName)
+	// MaxSharedLocks가 0이면 제한 없음(-1)으로 취급
+	if params.MaxSharedLocks == 0 {
+		params.MaxSharedLocks = -1
+	}
 
-	result, err := transaction.ExecContext(
-		ctx, ensureQuery,
-		params.Name, initialSharedLocks, params.MaxSharedLocks,
-	)
+	transaction, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
-		_ = transaction.Rollback()
 		return TrySLockResult{}, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	// 0. gate 잠금 후 트랜잭션 범위 보유자 확인 - 공유 보유자는 permit 1개씩 차지
+	txHolders, err := c.lockGate(ctx, transaction, params.Name, 0)
 	if err != nil {
 		_ = transaction.Rollback()
 		return TrySLockResult{}, err
 	}
-
-	if rowsAffected > 0 {
-		// 새로 생성되어 바로 획득 성공
-		if err := transaction.Commit(); err != nil {
-			return TrySLockResult{}, err
-		}
-
-		return TrySLockResult{ExpiresAt: newExpiresAt, Acquired: true}, nil
+	if txHolders.exclusive > 0 {
+		_ = transaction.Rollback()
+		c.logConflict(ctx, LockModeShared, params.Name, params.LockID, conflictExclusiveHolder, slog.String("holder", "transaction"))
+		return TrySLockResult{Acquired: false}, nil
 	}
 
-	// 2. FOR UPDATE로 행 잠금 및 현재 상태 조회
-	// 주의: SLock 간에도 JSONB 배열 업데이트 시 race condition 방지를 위해 필요
-	selectQuery := fmt.Sprintf(`
-		SELECT xlock_id, x_expires_at, shared_locks, max_shared_locks
-		FROM %s
-		WHERE name = $1
-		FOR UPDATE;
-	`, tableName)
-
-	var xlockID sql.NullString
-	var xExpiresAt sql.NullTime
-	var sharedLocksJSON []byte
-	var maxSharedLocks int
-
-	err = transaction.QueryRowContext(ctx, selectQuery, params.Name).Scan(
-		&xlockID, &xExpiresAt, &sharedLocksJSON, &maxSharedLocks,
-	)
+	// 정책 조회 - 정책이 있으면 행을 누가 만들었는지와 무관하게 정책의 제한을 적용
+	policy, _, err := c.findLockPolicy(ctx, transaction, params.Name)
 	if err != nil {
 		_ = transaction.Rollback()
 		return TrySLockResult{}, err
 	}
+	params.TTLSeconds = policy.resolveTTL(params.TTLSeconds)
 
-	// 3. XLock 확인
-	if xlockID.Valid && xExpiresAt.Valid && xExpiresAt.Time.After(time.Now()) {
+	// 세션에 묶인 요청이면 세션이 살아 있어야 함
+	if err := c.checkSession(ctx, transaction, params.SessionID); err != nil {
 		_ = transaction.Rollback()
-		return TrySLockResult{Acquired: false}, nil
+		return TrySLockResult{}, err
 	}
 
-	// 4. 만료되지 않은 SLock만 필터링
-	var sharedLocks []SharedLockEntry
-	if len(sharedLocksJSON) > 0 {
-		if err := json.Unmarshal(sharedLocksJSON, &sharedLocks); err != nil {
+	now := time.Now()
+
+	// 1. lock 행 생성 (없으면) - 요청한 permit 수가 최대치를 넘으면 생성하지 않음
+	empty := lockRow{maxSharedLocks: params.MaxSharedLocks}
+	created, emptyConflict := acquireSLock(empty, params, policy.resolveMaxSharedLocks(params.MaxSharedLocks), txHolders.shared, now)
+	if emptyConflict == nil {
+		inserted, err := c.insertLockRow(ctx, transaction, params.Name, created.row)
+		if err != nil {
 			_ = transaction.Rollback()
-			return TrySLockResult{}, fmt.Errorf("failed to parse shared_locks: %w", err)
+			return TrySLockResult{}, err
+		}
+		if inserted {
+			return c.commitSLock(ctx, transaction, params, created)
 		}
 	}
 
-	validLocks := []SharedLockEntry{}
-	alreadyHasLock := false
-	for _, lock := range sharedLocks {
-		if lock.ExpiresAt.After(time.Now()) {
-			if lock.LockID == params.LockID {
-				alreadyHasLock = true
-				// 자기 자신의 락도 validLocks에 일단 포함 (갱신용)
-				validLocks = append(validLocks, lock)
-			} else {
-				validLocks = append(validLocks, lock)
-			}
-		}
+	// 2. FOR UPDATE로 행 잠금 및 현재 상태 조회
+	// 주의: SLock 간에도 JSONB 배열 업데이트 시 race condition 방지를 위해 필요
+	row, err := c.selectLockRowForUpdate(ctx, transaction, params.Name)
+	if err == sql.ErrNoRows && emptyConflict != nil {
+		_ = transaction.Rollback()
+		c.logConflict(ctx, LockModeShared, params.Name, params.LockID, emptyConflict.reason, emptyConflict.attrs...)
+		return TrySLockResult{Acquired: false}, nil
+	}
+	if err != nil {
+		_ = transaction.Rollback()
+		return TrySLockResult{}, err
 	}
 
-	// 5. 새로운 락 추가 여부 결정 및 개수 제한 확인
-	if !alreadyHasLock {
-		// 새 락을 추가할 때만 개수 제한 확인
-		if maxSharedLocks != -1 && len(validLocks) >= maxSharedLocks {
-			_ = transaction.Rollback()
-			return TrySLockResult{Acquired: false}, nil
-		}
+	// 종료된 세션에 묶인 보유자는 TTL과 무관하게 만료된 것으로 취급
+	if err := c.expireDeadSessionHolders(ctx, transaction, &row, now); err != nil {
+		_ = transaction.Rollback()
+		return TrySLockResult{}, err
 	}
 
-	// 6. SLock 추가 또는 갱신
-	// newExpiresAt는 line 250에서 이미 계산됨
-	if alreadyHasLock {
-		// 기존 락 갱신
-		for i := range validLocks {
-			if validLocks[i].LockID == params.LockID {
-				validLocks[i].ExpiresAt = newExpiresAt
-				break
-			}
-		}
-	} else {
-		// 새 락 추가
-		validLocks = append(validLocks, SharedLockEntry{
-			LockID:    params.LockID,
-			ExpiresAt: newExpiresAt,
-		})
+	// 3. XLock과 permit 합계 제한 확인 후 SLock 추가 또는 갱신
+	change, conflict := acquireSLock(row, params, policy.resolveMaxSharedLocks(row.maxSharedLocks), txHolders.shared, now)
+	if conflict != nil {
+		_ = transaction.Rollback()
+		c.logConflict(ctx, LockModeShared, params.Name, params.LockID, conflict.reason, conflict.attrs...)
+		return TrySLockResult{Acquired: false}, nil
 	}
 
-	newSharedLocksJSON, err := json.Marshal(validLocks)
-	if err != nil {
+	if err := c.updateLockRow(ctx, transaction, params.Name, change.row); err != nil {
 		_ = transaction.Rollback()
-		return TrySLockResult{}, fmt.Errorf("failed to marshal shared_locks: %w", err)
+		return TrySLockResult{}, err
 	}
 
-	// 7. shared_locks 업데이트
-	updateQuery := fmt.Sprintf(`
-		UPDATE %s
-		SET shared_locks = $1
-		WHERE name = $2;
-	`, tableName)
-	_, err = transaction.ExecContext(ctx, updateQuery, newSharedLocksJSON, params.Name)
-	if err != nil {
+	return c.commitSLock(ctx, transaction, params, change)
+}
+
+// commitSLock records the audit events of an acquired SLock and commits its transaction.
+func (c *lockClient) commitSLock(ctx context.Context, transaction *sql.Tx, params TrySLockParams, change lockChange) (TrySLockResult, error) {
+	if err := c.recordAuditEvents(ctx, transaction, change.events...); err != nil {
 		_ = transaction.Rollback()
 		return TrySLockResult{}, err
 	}
@@ -397,34 +480,65 @@ func (c *lockClient) TrySLock(ctx context.Context, params TrySLockParams) (TrySL
 		return TrySLockResult{}, err
 	}
 
-	return TrySLockResult{ExpiresAt: newExpiresAt, Acquired: true}, nil
+	c.logAcquired(ctx, LockModeShared, params.Name, params.LockID, change.expiresAt)
+
+	return TrySLockResult{ExpiresAt: change.expiresAt, Acquired: true}, nil
 }
 
 // SLock continuously attempts to acquire a shared lock until successful.
 func (c *lockClient) SLock(ctx context.Context, params SLockParams) (SLockResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.SLock", LockModeShared, params.Name, params.LockID)
+
+	result, attempts, err := c.sLock(ctx, params)
+
+	endWaitSpan(span, attempts, time.Since(startedAt), err == nil, err)
+	c.observe(LockEvent{
+		Operation: OperationSLock,
+		Name:      params.Name,
+		Mode:      LockModeShared,
+		Outcome:   outcomeOf(err, true, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  attempts,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+// sLock returns the result of SLock along with the number of attempts made.
+func (c *lockClient) sLock(ctx context.Context, params SLockParams) (SLockResult, int, error) {
 	// IntervalDuration이 0 이하면 기본값 사용 (타이트 루프 방지)
 	if params.IntervalDuration <= 0 {
 		params.IntervalDuration = DefaultRetryInterval
 	}
 
+	attempts := 0
 	for {
-		result, err := c.TrySLock(ctx, TrySLockParams{
+		attempts++
+		c.logAttempt(ctx, LockModeShared, params.Name, params.LockID, attempts)
+
+		result, err := c.tracedTrySLock(ctx, TrySLockParams{
 			Name:           params.Name,
 			LockID:         params.LockID,
 			TTLSeconds:     params.TTLSeconds,
 			MaxSharedLocks: params.MaxSharedLocks,
+			Weight:         params.Weight,
+			SessionID:      params.SessionID,
+			Metadata:       params.Metadata,
+			Owner:          params.Owner,
 		})
 		if err != nil {
-			return SLockResult{}, err
+			return SLockResult{}, attempts, err
 		}
 		if result.Acquired {
-			return SLockResult{ExpiresAt: result.ExpiresAt}, nil
+			return SLockResult{ExpiresAt: result.ExpiresAt}, attempts, nil
 		}
 
 		// 컨텍스트 취소 확인
 		select {
 		case <-ctx.Done():
-			return SLockResult{}, ctx.Err()
+			return SLockResult{}, attempts, ctx.Err()
 		case <-time.After(params.IntervalDuration):
 			// 재시도
 		}
@@ -434,29 +548,35 @@ func (c *lockClient) SLock(ctx context.Context, params SLockParams) (SLockResult
 // Unlock releases the lock if we still own it (either XLock or SLock).
 // Returns whether the lock was released and any error.
 func (c *lockClient) Unlock(ctx context.Context, params UnlockParams) (UnlockResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.Unlock", "", params.Name, params.LockID)
+
+	result, err := c.unlock(ctx, params)
+
+	span.SetAttributes(AttributeReleased.Bool(result.Released))
+	endSpan(span, err)
+
+	c.observe(LockEvent{
+		Operation: OperationUnlock,
+		Name:      params.Name,
+		Outcome:   outcomeOf(err, result.Released, OutcomeReleased, OutcomeNotHeld),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *lockClient) unlock(ctx context.Context, params UnlockParams) (UnlockResult, error) {
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return UnlockResult{}, err
 	}
 	defer tx.Rollback()
 
-	tableName := c.options.LockTableName
-
 	// 1. 현재 상태 조회 및 행 잠금 (FOR UPDATE)
-	selectQuery := fmt.Sprintf(`
-		SELECT xlock_id, x_expires_at, shared_locks
-		FROM %s
-		WHERE name = $1
-		FOR UPDATE;
-	`, tableName)
-
-	var xlockID sql.NullString
-	var xExpiresAt sql.NullTime
-	var sharedLocksJSON []byte
-
-	err = tx.QueryRowContext(ctx, selectQuery, params.Name).Scan(
-		&xlockID, &xExpiresAt, &sharedLocksJSON,
-	)
+	row, err := c.selectLockRowForUpdate(ctx, tx, params.Name)
 	if err == sql.ErrNoRows {
 		// 락이 존재하지 않음
 		return UnlockResult{Released: false}, nil
@@ -465,60 +585,168 @@ func (c *lockClient) Unlock(ctx context.Context, params UnlockParams) (UnlockRes
 		return UnlockResult{}, err
 	}
 
-	released := false
-
-	// 2. XLock 확인 및 제거
-	if xlockID.Valid && xlockID.String == params.LockID {
-		updateQuery := fmt.Sprintf(`
-			UPDATE %s
-			SET xlock_id = NULL, x_expires_at = NULL
-			WHERE name = $1;
-		`, tableName)
-		_, err = tx.ExecContext(ctx, updateQuery, params.Name)
-		if err != nil {
+	// 2. XLock 또는 SLock 제거
+	change := releaseHolder(row, params, time.Now())
+	released := len(change.events) > 0
+	if released {
+		if err := c.updateLockRow(ctx, tx, params.Name, change.row); err != nil {
 			return UnlockResult{}, err
 		}
-		released = true
 	}
 
-	// 3. SLock 확인 및 제거
-	var sharedLocks []SharedLockEntry
-	if len(sharedLocksJSON) > 0 {
-		if err := json.Unmarshal(sharedLocksJSON, &sharedLocks); err != nil {
-			return UnlockResult{}, fmt.Errorf("failed to parse shared_locks: %w", err)
-		}
+	if err := c.recordAuditEvents(ctx, tx, change.events...); err != nil {
+		return UnlockResult{}, err
 	}
 
-	newSharedLocks := []SharedLockEntry{}
-	for _, lock := range sharedLocks {
-		if lock.LockID != params.LockID {
-			newSharedLocks = append(newSharedLocks, lock)
-		} else {
-			released = true
-		}
+	err = tx.Commit()
+	if err != nil {
+		return UnlockResult{}, err
 	}
 
-	if len(newSharedLocks) != len(sharedLocks) {
-		// SLock이 제거되었으면 업데이트
-		newSharedLocksJSON, err := json.Marshal(newSharedLocks)
-		if err != nil {
-			return UnlockResult{}, fmt.Errorf("failed to marshal shared_locks: %w", err)
-		}
-		updateQuery := fmt.Sprintf(`
-			UPDATE %s
-			SET shared_locks = $1
-			WHERE name = $2;
-		`, tableName)
-		_, err = tx.ExecContext(ctx, updateQuery, newSharedLocksJSON, params.Name)
-		if err != nil {
-			return UnlockResult{}, err
-		}
+	c.logReleased(ctx, params.Name, params.LockID, released)
+
+	return UnlockResult{Released: released}, nil
+}
+
+type RefreshParams struct {
+	Name       string // Lock Name: unique identifier for the lock
+	LockID     string // Lock LockID: identifier for the entity holding the lock
+	TTLSeconds int    // Time-To-Live: new duration in seconds for the lock, counted from now
+}
+
+type RefreshResult struct {
+	ExpiresAt time.Time // New expiration time of the lock
+	Refreshed bool      // Whether the lock was still held and has been extended
+}
+
+// Refresh extends the TTL of a lock (either XLock or SLock) that we still hold.
+// A lock that has already expired is not re-acquired; Refreshed is false in that case.
+func (c *lockClient) Refresh(ctx context.Context, params RefreshParams) (RefreshResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.Refresh", "", params.Name, params.LockID)
+
+	result, err := c.refresh(ctx, params)
+
+	span.SetAttributes(AttributeRefreshed.Bool(result.Refreshed))
+	endSpan(span, err)
+
+	c.observe(LockEvent{
+		Operation: OperationRefresh,
+		Name:      params.Name,
+		Outcome:   outcomeOf(err, result.Refreshed, OutcomeRefreshed, OutcomeLost),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *lockClient) refresh(ctx context.Context, params RefreshParams) (RefreshResult, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return RefreshResult{}, err
 	}
+	defer tx.Rollback()
 
-	err = tx.Commit()
+	policy, _, err := c.findLockPolicy(ctx, tx, params.Name)
 	if err != nil {
-		return UnlockResult{}, err
+		return RefreshResult{}, err
 	}
+	params.TTLSeconds = policy.resolveTTL(params.TTLSeconds)
 
-	return UnlockResult{Released: released}, nil
+	// 1. 현재 상태 조회 및 행 잠금 (FOR UPDATE)
+	row, err := c.selectLockRowForUpdate(ctx, tx, params.Name)
+	if err == sql.ErrNoRows {
+		// 락이 존재하지 않음
+		c.logRefreshLost(ctx, params.Name, params.LockID)
+		return RefreshResult{Refreshed: false}, nil
+	}
+	if err != nil {
+		return RefreshResult{}, err
+	}
+
+	// 종료된 세션에 묶인 락은 연장하지 않음
+	now := time.Now()
+	if err := c.expireDeadSessionHolders(ctx, tx, &row, now); err != nil {
+		return RefreshResult{}, err
+	}
+
+	// 2. 유효한 XLock 또는 SLock 보유 시 연장
+	change, mode := refreshHolder(row, params, now)
+	if mode == "" {
+		c.logRefreshLost(ctx, params.Name, params.LockID)
+		return RefreshResult{Refreshed: false}, nil
+	}
+
+	if err := c.updateLockRow(ctx, tx, params.Name, change.row); err != nil {
+		return RefreshResult{}, err
+	}
+
+	if err := c.recordAuditEvents(ctx, tx, change.events...); err != nil {
+		return RefreshResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return RefreshResult{}, err
+	}
+
+	c.logRefreshed(ctx, mode, params.Name, params.LockID, change.expiresAt)
+
+	return RefreshResult{ExpiresAt: change.expiresAt, Refreshed: true}, nil
+}
+
+type ForceUnlockParams struct {
+	Name     string          // Lock Name: unique identifier for the lock
+	Metadata json.RawMessage // [optional] JSON recorded with the audit events (e.g. who forced the release and why)
+}
+
+type ForceUnlockResult struct {
+	XLockID       string   // LockID of the live exclusive holder that was removed ("" if none)
+	SharedLockIDs []string // LockIDs of the live shared holders that were removed
+	Released      bool     // Whether any live holder was removed
+}
+
+// ForceUnlock removes every holder of a lock regardless of who owns it.
+// It is meant for operators recovering from a stuck holder.
+func (c *lockClient) ForceUnlock(ctx context.Context, params ForceUnlockParams) (ForceUnlockResult, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ForceUnlockResult{}, err
+	}
+	defer tx.Rollback()
+
+	// 1. 현재 상태 조회 및 행 잠금 (FOR UPDATE)
+	row, err := c.selectLockRowForUpdate(ctx, tx, params.Name)
+	if err == sql.ErrNoRows {
+		// 락이 존재하지 않음
+		return ForceUnlockResult{SharedLockIDs: []string{}}, nil
+	}
+	if err != nil {
+		return ForceUnlockResult{}, err
+	}
+
+	// 2. 종료된 세션에 묶인 보유자는 유효한 보유자에서 제외
+	now := time.Now()
+	if err := c.expireDeadSessionHolders(ctx, tx, &row, now); err != nil {
+		return ForceUnlockResult{}, err
+	}
+
+	// 3. 모든 보유자 제거
+	change, result := forceRelease(row, params, now)
+	if err := c.updateLockRow(ctx, tx, params.Name, change.row); err != nil {
+		return ForceUnlockResult{}, err
+	}
+
+	if err := c.recordAuditEvents(ctx, tx, change.events...); err != nil {
+		return ForceUnlockResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ForceUnlockResult{}, err
+	}
+
+	c.logForceUnlocked(ctx, params.Name, result.Released)
+
+	return result, nil
 }
@@ -2,8 +2,10 @@ package pglock
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -397,3 +399,299 @@ func TestLockExpiration(t *testing.T) {
 		LockID: "lock_2",
 	})
 }
+
+// TestSLock_Weighted tests that MaxSharedLocks limits the sum of weights
+func TestSLock_Weighted(t *testing.T) {
+	client := setupTestDB(t)
+	ctx := context.Background()
+
+	// 1. weight 5로 10개 중 5개 획득
+	result1, err := client.TrySLock(ctx, TrySLockParams{
+		Name:           "test_slock_weighted",
+		LockID:         "big_job",
+		TTLSeconds:     30,
+		MaxSharedLocks: 10,
+		Weight:         5,
+	})
+	require.NoError(t, err)
+	assert.True(t, result1.Acquired)
+
+	// 2. weight 1로 5개 추가 획득
+	for i := 0; i < 5; i++ {
+		result, err := client.TrySLock(ctx, TrySLockParams{
+			Name:           "test_slock_weighted",
+			LockID:         fmt.Sprintf("small_job_%d", i),
+			TTLSeconds:     30,
+			MaxSharedLocks: 10,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Acquired)
+	}
+
+	// 3. 남은 permit이 없으므로 실패해야 함
+	result2, err := client.TrySLock(ctx, TrySLockParams{
+		Name:           "test_slock_weighted",
+		LockID:         "small_job_5",
+		TTLSeconds:     30,
+		MaxSharedLocks: 10,
+	})
+	require.NoError(t, err)
+	assert.False(t, result2.Acquired)
+
+	// 4. 큰 작업이 해제되면 다시 획득 가능
+	_, err = client.Unlock(ctx, UnlockParams{
+		Name:   "test_slock_weighted",
+		LockID: "big_job",
+	})
+	require.NoError(t, err)
+
+	result3, err := client.TrySLock(ctx, TrySLockParams{
+		Name:           "test_slock_weighted",
+		LockID:         "small_job_5",
+		TTLSeconds:     30,
+		MaxSharedLocks: 10,
+		Weight:         5,
+	})
+	require.NoError(t, err)
+	assert.True(t, result3.Acquired)
+
+	// 정리
+	for i := 0; i <= 5; i++ {
+		client.Unlock(ctx, UnlockParams{
+			Name:   "test_slock_weighted",
+			LockID: fmt.Sprintf("small_job_%d", i),
+		})
+	}
+}
+
+// TestLockPolicy_OverridesParams tests that a lock policy is applied regardless of who created the row
+func TestLockPolicy_OverridesParams(t *testing.T) {
+	client := setupTestDB(t)
+	ctx := context.Background()
+
+	// 1. prefix 정책 설정: 최대 2개, TTL 최대 10초
+	_, err := client.SetLockPolicy(ctx, SetLockPolicyParams{
+		Name:           "test_policy/",
+		Prefix:         true,
+		MaxSharedLocks: 2,
+		MaxTTLSeconds:  10,
+	})
+	require.NoError(t, err)
+	defer client.DeleteLockPolicy(ctx, DeleteLockPolicyParams{Name: "test_policy/", Prefix: true})
+
+	policy, err := client.GetLockPolicy(ctx, GetLockPolicyParams{Name: "test_policy/a"})
+	require.NoError(t, err)
+	assert.True(t, policy.Found)
+	assert.Equal(t, 2, policy.Policy.MaxSharedLocks)
+
+	// 2. 무제한을 요청해도 정책의 제한이 적용되어야 함
+	for i := 0; i < 3; i++ {
+		result, err := client.TrySLock(ctx, TrySLockParams{
+			Name:           "test_policy/a",
+			LockID:         fmt.Sprintf("reader_%d", i),
+			TTLSeconds:     60,
+			MaxSharedLocks: -1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, i < 2, result.Acquired)
+		if i == 0 {
+			// TTL은 최대 10초로 제한
+			assert.WithinDuration(t, time.Now().Add(10*time.Second), result.ExpiresAt, 2*time.Second)
+		}
+	}
+
+	// 정리
+	for i := 0; i < 3; i++ {
+		client.Unlock(ctx, UnlockParams{
+			Name:   "test_policy/a",
+			LockID: fmt.Sprintf("reader_%d", i),
+		})
+	}
+}
+
+// TestAudit_RecordsHistory tests that lock events are recorded in the audit table
+func TestAudit_RecordsHistory(t *testing.T) {
+	setupTestDB(t)
+	ctx := context.Background()
+
+	client := NewLockClient(LockClientOptions{
+		DatabaseURL: testDBURL,
+		EnableAudit: true,
+	})
+	require.NoError(t, client.Initialize())
+
+	startedAt := time.Now()
+
+	// 1. 획득 → 갱신 → 해제 → 강제 해제
+	_, err := client.TryXLock(ctx, TryXLockParams{
+		Name:       "test_audit",
+		LockID:     "writer_1",
+		TTLSeconds: 30,
+		Metadata:   []byte(`{"job":"nightly"}`),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Refresh(ctx, RefreshParams{
+		Name:       "test_audit",
+		LockID:     "writer_1",
+		TTLSeconds: 30,
+	})
+	require.NoError(t, err)
+
+	_, err = client.Unlock(ctx, UnlockParams{
+		Name:   "test_audit",
+		LockID: "writer_1",
+	})
+	require.NoError(t, err)
+
+	_, err = client.TrySLock(ctx, TrySLockParams{
+		Name:           "test_audit",
+		LockID:         "reader_1",
+		TTLSeconds:     30,
+		MaxSharedLocks: -1,
+	})
+	require.NoError(t, err)
+
+	forced, err := client.ForceUnlock(ctx, ForceUnlockParams{Name: "test_audit"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reader_1"}, forced.SharedLockIDs)
+
+	// 2. 이력 확인
+	history, err := client.GetLockHistory(ctx, GetLockHistoryParams{
+		Name: "test_audit",
+		From: startedAt,
+	})
+	require.NoError(t, err)
+
+	events := []AuditEventType{}
+	for _, event := range history.Events {
+		events = append(events, event.Event)
+	}
+	assert.Equal(t, []AuditEventType{
+		AuditEventAcquire, AuditEventRefresh, AuditEventRelease, AuditEventAcquire, AuditEventForceUnlock,
+	}, events)
+	assert.JSONEq(t, `{"job":"nightly"}`, string(history.Events[0].Metadata))
+}
+
+// TestXLockTx_ReleasedAtCommit tests that a transaction-scoped lock excludes other holders until the transaction ends
+func TestXLockTx_ReleasedAtCommit(t *testing.T) {
+	setupTestDB(t)
+	ctx := context.Background()
+
+	client := NewLockClient(LockClientOptions{
+		DatabaseURL:   testDBURL,
+		EnableTxLocks: true,
+	})
+	require.NoError(t, client.Initialize())
+
+	db, err := sql.Open("postgres", testDBURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// 1. 호출자 트랜잭션에서 XLock 획득
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	txResult, err := client.TryXLockTx(ctx, tx, TryXLockTxParams{Name: "test_tx_lock", LockID: "tx_holder"})
+	require.NoError(t, err)
+	assert.True(t, txResult.Acquired)
+
+	// 2. 트랜잭션이 끝날 때까지 일반 락과 다른 트랜잭션의 락은 실패해야 함
+	result, err := client.TryXLock(ctx, TryXLockParams{Name: "test_tx_lock", LockID: "worker", TTLSeconds: 10})
+	require.NoError(t, err)
+	assert.False(t, result.Acquired)
+
+	sharedResult, err := client.TrySLock(ctx, TrySLockParams{Name: "test_tx_lock", LockID: "reader", TTLSeconds: 10, MaxSharedLocks: -1})
+	require.NoError(t, err)
+	assert.False(t, sharedResult.Acquired)
+
+	otherTx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	otherResult, err := client.TrySLockTx(ctx, otherTx, TrySLockTxParams{Name: "test_tx_lock", LockID: "tx_reader", MaxSharedLocks: -1})
+	require.NoError(t, err)
+	assert.False(t, otherResult.Acquired)
+	require.NoError(t, otherTx.Rollback())
+
+	// 3. 커밋하면 별도의 Unlock 없이 해제됨
+	require.NoError(t, tx.Commit())
+
+	result, err = client.TryXLock(ctx, TryXLockParams{Name: "test_tx_lock", LockID: "worker", TTLSeconds: 10})
+	require.NoError(t, err)
+	assert.True(t, result.Acquired)
+
+	// 4. 일반 XLock이 보유 중이면 트랜잭션 범위 락은 실패
+	tx, err = db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	txResult, err = client.TryXLockTx(ctx, tx, TryXLockTxParams{Name: "test_tx_lock", LockID: "tx_holder"})
+	require.NoError(t, err)
+	assert.False(t, txResult.Acquired)
+	require.NoError(t, tx.Rollback())
+
+	// 정리
+	client.Unlock(ctx, UnlockParams{Name: "test_tx_lock", LockID: "worker"})
+}
+
+// TestSLockTx_ConcurrentCapacity tests that concurrent transaction-scoped SLocks do not count each other's pending requests
+func TestSLockTx_ConcurrentCapacity(t *testing.T) {
+	setupTestDB(t)
+	ctx := context.Background()
+
+	client := NewLockClient(LockClientOptions{
+		DatabaseURL:   testDBURL,
+		EnableTxLocks: true,
+	})
+	require.NoError(t, client.Initialize())
+
+	db, err := sql.Open("postgres", testDBURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// 1. 허용량 1인 SLock을 여러 트랜잭션이 동시에 요청하면 정확히 하나만 성공해야 함
+	const requests = 8
+	var acquired atomic.Int32
+	var wg sync.WaitGroup
+	txs := make([]*sql.Tx, requests)
+	for i := range txs {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		txs[i] = tx
+	}
+
+	for i, tx := range txs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := client.TrySLockTx(ctx, tx, TrySLockTxParams{
+				Name:           "test_tx_capacity",
+				LockID:         fmt.Sprintf("tx_reader_%d", i),
+				MaxSharedLocks: 1,
+			})
+			assert.NoError(t, err)
+			if result.Acquired {
+				acquired.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), acquired.Load())
+}
+
+// TestXLockTx_Disabled tests that transaction-scoped locks are rejected unless EnableTxLocks is set
+func TestXLockTx_Disabled(t *testing.T) {
+	client := setupTestDB(t)
+	ctx := context.Background()
+
+	db, err := sql.Open("postgres", testDBURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = client.TryXLockTx(ctx, tx, TryXLockTxParams{Name: "test_tx_disabled", LockID: "tx_holder"})
+	assert.ErrorIs(t, err, ErrTxLocksDisabled)
+}
@@ -0,0 +1,263 @@
+package pglock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultLockerTTLSeconds is the default lease TTL of the locks taken by Locker and RWLocker
+	DefaultLockerTTLSeconds = 30
+)
+
+type LockerOptions struct {
+	Name           string          // [required] Lock name guarded by the locker
+	LockID         string          // [optional] LockID used for the locks. default: "<hostname>-<pid>-<sequence>", unique per locker
+	TTLSeconds     int             // [optional] Lease TTL. default: 30
+	RenewInterval  time.Duration   // [optional] Lease renewal interval while the lock is held. default: TTL / 3
+	RetryInterval  time.Duration   // [optional] Interval between acquisition attempts. default: 100ms
+	MaxSharedLocks int             // [optional] Maximum number of processes holding RLock at once (-1 for unlimited). default: -1
+	OnError        func(err error) // [optional] Receives the errors Lock and Unlock cannot return, and ErrLockLost when a held lease is lost. default: ignored
+}
+
+var lockerSequence atomic.Int64
+
+// newProcessLockID returns a LockID of the form "<hostname>-<pid>-<sequence>", unique within the process.
+func newProcessLockID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), lockerSequence.Add(1))
+}
+
+func (options *LockerOptions) SetDefaults() {
+	if options.LockID == "" {
+		options.LockID = newProcessLockID()
+	}
+	if options.TTLSeconds <= 0 {
+		options.TTLSeconds = DefaultLockerTTLSeconds
+	}
+	if options.RenewInterval <= 0 {
+		options.RenewInterval = time.Duration(options.TTLSeconds) * time.Second / 3
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = DefaultRetryInterval
+	}
+	if options.MaxSharedLocks == 0 {
+		options.MaxSharedLocks = -1
+	}
+	if options.OnError == nil {
+		options.OnError = func(error) {}
+	}
+}
+
+// lockerLease is a lock held by a locker, renewed in the background until it is released.
+type lockerLease struct {
+	stopRenewal context.CancelFunc
+	renewalDone chan struct{}
+}
+
+// lockerBase acquires and releases the locks of Locker and RWLocker.
+type lockerBase struct {
+	client  LockClient
+	options LockerOptions
+}
+
+// acquire blocks until the lock is held in the given mode.
+// Errors are reported to OnError and the acquisition is retried, since Lock cannot fail.
+func (l *lockerBase) acquire(mode LockMode) *lockerLease {
+	for {
+		var expiresAt time.Time
+		var err error
+
+		if mode == LockModeExclusive {
+			var result XLockResult
+			result, err = l.client.XLock(context.Background(), XLockParams{
+				Name:             l.options.Name,
+				LockID:           l.options.LockID,
+				TTLSeconds:       l.options.TTLSeconds,
+				IntervalDuration: l.options.RetryInterval,
+			})
+			expiresAt = result.ExpiresAt
+		} else {
+			var result SLockResult
+			result, err = l.client.SLock(context.Background(), SLockParams{
+				Name:             l.options.Name,
+				LockID:           l.options.LockID,
+				TTLSeconds:       l.options.TTLSeconds,
+				MaxSharedLocks:   l.options.MaxSharedLocks,
+				IntervalDuration: l.options.RetryInterval,
+			})
+			expiresAt = result.ExpiresAt
+		}
+
+		if err == nil {
+			return l.keepAlive(expiresAt)
+		}
+
+		l.options.OnError(err)
+		time.Sleep(l.options.RetryInterval)
+	}
+}
+
+// keepAlive renews the held lock until the lease is released, reporting a lost lease to OnError.
+func (l *lockerBase) keepAlive(expiresAt time.Time) *lockerLease {
+	ctx, cancel := context.WithCancel(context.Background())
+	lost := KeepAlive(ctx, l.client, KeepAliveParams{
+		Name:             l.options.Name,
+		LockID:           l.options.LockID,
+		TTLSeconds:       l.options.TTLSeconds,
+		ExpiresAt:        expiresAt,
+		IntervalDuration: l.options.RenewInterval,
+	})
+
+	lease := &lockerLease{
+		stopRenewal: cancel,
+		renewalDone: make(chan struct{}),
+	}
+	go func() {
+		defer close(lease.renewalDone)
+
+		for err := range lost {
+			l.options.OnError(err)
+		}
+	}()
+
+	return lease
+}
+
+// release stops renewing the lease and releases the lock.
+func (l *lockerBase) release(lease *lockerLease) {
+	lease.stopRenewal()
+	<-lease.renewalDone
+
+	if _, err := l.client.Unlock(context.Background(), UnlockParams{
+		Name:   l.options.Name,
+		LockID: l.options.LockID,
+	}); err != nil {
+		l.options.OnError(err)
+	}
+}
+
+// Locker is a sync.Locker backed by an exclusive lock.
+// Goroutines of the same process are serialized locally, so a Locker can be shared like a sync.Mutex.
+type Locker struct {
+	lockerBase
+
+	mu    sync.Mutex
+	lease *lockerLease
+}
+
+var _ sync.Locker = (*Locker)(nil)
+
+// NewLocker returns a Locker that takes the exclusive lock options.Name through client.
+func NewLocker(client LockClient, options LockerOptions) *Locker {
+	options.SetDefaults()
+
+	return &Locker{
+		lockerBase: lockerBase{client: client, options: options},
+	}
+}
+
+// Lock blocks until the exclusive lock is acquired. The lease is renewed until Unlock is called.
+func (l *Locker) Lock() {
+	l.mu.Lock()
+	l.lease = l.acquire(LockModeExclusive)
+}
+
+// Unlock releases the exclusive lock. As with sync.Mutex, it is a run-time error if l is not locked.
+func (l *Locker) Unlock() {
+	if l.lease == nil {
+		panic("pglock: unlock of unlocked Locker")
+	}
+
+	l.release(l.lease)
+	l.lease = nil
+	l.mu.Unlock()
+}
+
+// RWLocker is a reader/writer lock backed by an exclusive lock (Lock) and a shared lock (RLock).
+// Readers of the same process share a single shared lock, taken by the first RLock and released by the last RUnlock.
+type RWLocker struct {
+	lockerBase
+
+	rw         sync.RWMutex // serializes writers and readers of this process
+	writeLease *lockerLease
+
+	readMu    sync.Mutex // guards readers and readLease
+	readers   int
+	readLease *lockerLease
+}
+
+// NewRWLocker returns an RWLocker for the lock options.Name through client.
+func NewRWLocker(client LockClient, options LockerOptions) *RWLocker {
+	options.SetDefaults()
+
+	return &RWLocker{
+		lockerBase: lockerBase{client: client, options: options},
+	}
+}
+
+// Lock blocks until the exclusive lock is acquired.
+func (l *RWLocker) Lock() {
+	l.rw.Lock()
+	l.writeLease = l.acquire(LockModeExclusive)
+}
+
+// Unlock releases the exclusive lock. It is a run-time error if l is not locked for writing.
+func (l *RWLocker) Unlock() {
+	if l.writeLease == nil {
+		panic("pglock: unlock of unlocked RWLocker")
+	}
+
+	l.release(l.writeLease)
+	l.writeLease = nil
+	l.rw.Unlock()
+}
+
+// RLock blocks until the shared lock is held by this process.
+func (l *RWLocker) RLock() {
+	l.rw.RLock()
+
+	l.readMu.Lock()
+	defer l.readMu.Unlock()
+
+	if l.readers == 0 {
+		l.readLease = l.acquire(LockModeShared)
+	}
+	l.readers++
+}
+
+// RUnlock undoes a single RLock call. The shared lock is released when the last reader leaves.
+func (l *RWLocker) RUnlock() {
+	l.readMu.Lock()
+	if l.readers <= 0 {
+		l.readMu.Unlock()
+		panic("pglock: RUnlock of unlocked RWLocker")
+	}
+
+	l.readers--
+	if l.readers == 0 {
+		l.release(l.readLease)
+		l.readLease = nil
+	}
+	l.readMu.Unlock()
+
+	l.rw.RUnlock()
+}
+
+// RLocker returns a sync.Locker that calls RLock and RUnlock.
+func (l *RWLocker) RLocker() sync.Locker {
+	return (*rlocker)(l)
+}
+
+type rlocker RWLocker
+
+func (r *rlocker) Lock()   { (*RWLocker)(r).RLock() }
+func (r *rlocker) Unlock() { (*RWLocker)(r).RUnlock() }
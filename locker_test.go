@@ -0,0 +1,121 @@
+package pglock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocker_MutualExclusion tests that Lockers of different holders never run their critical sections at once
+func TestLocker_MutualExclusion(t *testing.T) {
+	client := NewMemoryLockClient(MemoryLockClientOptions{})
+
+	// 서로 다른 프로세스를 흉내 내는 두 Locker
+	lockers := []sync.Locker{
+		NewLocker(client, LockerOptions{Name: "test_locker", RetryInterval: time.Millisecond}),
+		NewLocker(client, LockerOptions{Name: "test_locker", RetryInterval: time.Millisecond}),
+	}
+
+	var wg sync.WaitGroup
+	counter := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(locker sync.Locker) {
+			defer wg.Done()
+
+			locker.Lock()
+			defer locker.Unlock()
+
+			value := counter
+			time.Sleep(time.Millisecond)
+			counter = value + 1
+		}(lockers[i%2])
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, counter)
+
+	// 모두 해제되었는지 확인
+	result, err := client.DescribeLock(context.Background(), DescribeLockParams{Name: "test_locker"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Lock.XLockID)
+}
+
+// TestRWLocker_ReadersShareWritersExclude tests that readers of different holders share the lock and writers wait for them
+func TestRWLocker_ReadersShareWritersExclude(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryLockClient(MemoryLockClientOptions{})
+	reader1 := NewRWLocker(client, LockerOptions{Name: "test_rwlocker", LockID: "reader_1", RetryInterval: time.Millisecond})
+	reader2 := NewRWLocker(client, LockerOptions{Name: "test_rwlocker", LockID: "reader_2", RetryInterval: time.Millisecond})
+	writer := NewRWLocker(client, LockerOptions{Name: "test_rwlocker", LockID: "writer", RetryInterval: time.Millisecond})
+
+	// 1. 같은 프로세스의 여러 reader는 하나의 SLock을 공유
+	reader1.RLock()
+	reader1.RLocker().Lock()
+	reader2.RLock()
+
+	result, err := client.DescribeLock(ctx, DescribeLockParams{Name: "test_rwlocker"})
+	require.NoError(t, err)
+	assert.Len(t, result.Lock.SharedLocks, 2)
+
+	// 2. reader가 남아 있는 동안 writer는 대기
+	written := make(chan struct{})
+	go func() {
+		writer.Lock()
+		close(written)
+	}()
+
+	reader2.RUnlock()
+	reader1.RUnlock()
+	select {
+	case <-written:
+		t.Fatal("writer acquired the lock while a reader still held it")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	reader1.RLocker().Unlock()
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("writer was not able to acquire the lock after the readers left")
+	}
+
+	writer.Unlock()
+	assert.Panics(t, writer.Unlock)
+}
+
+// TestLocker_LeaseLost tests that losing the lease while locked is reported to OnError
+func TestLocker_LeaseLost(t *testing.T) {
+	client := NewMemoryLockClient(MemoryLockClientOptions{})
+
+	lost := make(chan error, 1)
+	locker := NewLocker(client, LockerOptions{
+		Name:          "test_locker_lost",
+		TTLSeconds:    1,
+		RenewInterval: 10 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case lost <- err:
+			default:
+			}
+		},
+	})
+
+	locker.Lock()
+	defer locker.Unlock()
+
+	_, err := client.ForceUnlock(context.Background(), ForceUnlockParams{Name: "test_locker_lost"})
+	require.NoError(t, err)
+
+	select {
+	case err := <-lost:
+		assert.True(t, errors.Is(err, ErrLockLost))
+	case <-time.After(time.Second):
+		t.Fatal("lease loss was not reported")
+	}
+}
@@ -0,0 +1,491 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// lockRow is the state of a lock: a row of the lock table, or a memoryLock for the in-memory client.
+// Both clients make every decision on it with the functions below, so they behave the same.
+type lockRow struct {
+	xlockID        string     // LockID of the exclusive holder ("" if none)
+	xExpiresAt     time.Time  // Expiration time of the exclusive lock
+	xSessionID     string     // Session the exclusive lock is bound to ("" if none)
+	xOwner         *OwnerInfo // Holder details of the exclusive lock
+	sharedLocks    []SharedLockEntry
+	maxSharedLocks int
+}
+
+// lockChange is the outcome of a decision on a lockRow.
+type lockChange struct {
+	row       lockRow          // State to store
+	events    []LockAuditEvent // Audit events to record
+	expiresAt time.Time        // New expiration time of the acquired or refreshed lock
+}
+
+// lockConflict is the reason an acquisition was refused, with the attributes to log.
+type lockConflict struct {
+	reason string
+	attrs  []slog.Attr
+}
+
+// liveSessionIDs returns the sessions that the live holders of the row are bound to.
+func (row *lockRow) liveSessionIDs(now time.Time) []string {
+	sessionIDs := []string{}
+	if row.xSessionID != "" && row.xExpiresAt.After(now) {
+		sessionIDs = append(sessionIDs, row.xSessionID)
+	}
+	for _, entry := range row.sharedLocks {
+		if entry.SessionID != "" && entry.ExpiresAt.After(now) && !slices.Contains(sessionIDs, entry.SessionID) {
+			sessionIDs = append(sessionIDs, entry.SessionID)
+		}
+	}
+
+	return sessionIDs
+}
+
+// expireDeadSessionHolders marks the live holders whose session is no longer alive as expired at now,
+// so that the usual expiry checks release them regardless of their own TTL.
+func (row *lockRow) expireDeadSessionHolders(alive func(sessionID string) bool, now time.Time) {
+	if row.xSessionID != "" && row.xExpiresAt.After(now) && !alive(row.xSessionID) {
+		row.xExpiresAt = now
+	}
+
+	for i := range row.sharedLocks {
+		entry := &row.sharedLocks[i]
+		if entry.SessionID != "" && entry.ExpiresAt.After(now) && !alive(entry.SessionID) {
+			entry.ExpiresAt = now
+		}
+	}
+}
+
+// heldExclusivelyBy reports whether lockID holds the live XLock of the row.
+func (row *lockRow) heldExclusivelyBy(lockID string, now time.Time) bool {
+	return row.xlockID != "" && row.xlockID == lockID && row.xExpiresAt.After(now)
+}
+
+// lockInfo returns the live holders of the row.
+func (row *lockRow) lockInfo(name string, now time.Time) LockInfo {
+	info := LockInfo{
+		Name:           name,
+		SharedLocks:    []SharedLockEntry{},
+		MaxSharedLocks: row.maxSharedLocks,
+	}
+
+	if row.xlockID != "" && row.xExpiresAt.After(now) {
+		info.XLockID = row.xlockID
+		info.XExpiresAt = row.xExpiresAt
+		info.XSessionID = row.xSessionID
+		info.XOwner = row.xOwner
+	}
+
+	for _, entry := range row.sharedLocks {
+		if entry.ExpiresAt.After(now) {
+			info.SharedLocks = append(info.SharedLocks, entry)
+		}
+	}
+
+	return info
+}
+
+// takeoverEvents returns the audit events for the expired holders that an acquisition clears.
+func (row *lockRow) takeoverEvents(name string, expiredSharedLocks []SharedLockEntry, now time.Time) []LockAuditEvent {
+	events := []LockAuditEvent{}
+	if row.xlockID != "" {
+		events = append(events, LockAuditEvent{
+			Name:       name,
+			LockID:     row.xlockID,
+			Mode:       LockModeExclusive,
+			Event:      AuditEventExpireTakeover,
+			OccurredAt: now,
+			ExpiresAt:  row.xExpiresAt,
+		})
+	}
+	for _, entry := range expiredSharedLocks {
+		events = append(events, LockAuditEvent{
+			Name:       name,
+			LockID:     entry.LockID,
+			Mode:       LockModeShared,
+			Event:      AuditEventExpireTakeover,
+			OccurredAt: now,
+			ExpiresAt:  entry.ExpiresAt,
+		})
+	}
+
+	return events
+}
+
+// acquireXLock decides whether params may take the XLock of the row.
+// On success the expired holders are cleared and params becomes the exclusive holder.
+func acquireXLock(row lockRow, params TryXLockParams, now time.Time) (lockChange, *lockConflict) {
+	// 1. 기존 XLock 확인
+	if row.xlockID != "" && row.xExpiresAt.After(now) {
+		return lockChange{}, &lockConflict{
+			reason: conflictExclusiveHolder,
+			attrs:  []slog.Attr{slog.String("holder", row.xlockID), slog.Time("holder_expires_at", row.xExpiresAt)},
+		}
+	}
+
+	// 2. 유효한 SLock 확인
+	for _, entry := range row.sharedLocks {
+		if entry.ExpiresAt.After(now) {
+			return lockChange{}, &lockConflict{
+				reason: conflictSharedHolders,
+				attrs:  []slog.Attr{slog.String("holder", entry.LockID), slog.Time("holder_expires_at", entry.ExpiresAt)},
+			}
+		}
+	}
+
+	// 3. XLock 설정 (남아 있던 만료된 보유자는 함께 정리)
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+	events := append(row.takeoverEvents(params.Name, row.sharedLocks, now), LockAuditEvent{
+		Name:       params.Name,
+		LockID:     params.LockID,
+		Mode:       LockModeExclusive,
+		Event:      AuditEventAcquire,
+		OccurredAt: now,
+		ExpiresAt:  expiresAt,
+		Metadata:   params.Metadata,
+	})
+
+	row.xlockID = params.LockID
+	row.xExpiresAt = expiresAt
+	row.xSessionID = params.SessionID
+	row.xOwner = newOwner(params.Owner, params.Metadata, now)
+	row.sharedLocks = []SharedLockEntry{}
+
+	return lockChange{row: row, events: events, expiresAt: expiresAt}, nil
+}
+
+// acquireSLock decides whether params may take (or renew) a shared lock on the row with at most
+// maxSharedLocks permits in total, reservedWeight of which are held outside the row.
+// On success the expired holders are cleared and the entry of params is added or renewed; the limit
+// stored in the row is left as the caller set it, so a policy limit only applies while the policy exists.
+func acquireSLock(row lockRow, params TrySLockParams, maxSharedLocks int, reservedWeight int, now time.Time) (lockChange, *lockConflict) {
+	// 1. XLock 확인
+	if row.xlockID != "" && row.xExpiresAt.After(now) {
+		return lockChange{}, &lockConflict{
+			reason: conflictExclusiveHolder,
+			attrs:  []slog.Attr{slog.String("holder", row.xlockID), slog.Time("holder_expires_at", row.xExpiresAt)},
+		}
+	}
+
+	// 2. 만료되지 않은 SLock만 필터링 (자기 자신의 락도 갱신을 위해 포함)
+	validLocks := []SharedLockEntry{}
+	expiredLocks := []SharedLockEntry{}
+	alreadyHasLock := false
+	currentWeight := 0
+	for _, entry := range row.sharedLocks {
+		if !entry.ExpiresAt.After(now) {
+			expiredLocks = append(expiredLocks, entry)
+			continue
+		}
+		if entry.LockID == params.LockID {
+			alreadyHasLock = true
+			currentWeight = entry.PermitWeight()
+		}
+		validLocks = append(validLocks, entry)
+	}
+
+	// 3. permit 합계 제한 확인
+	// 이미 보유한 락을 같은 weight 이하로 갱신하는 경우는 제한을 다시 확인하지 않음
+	if !alreadyHasLock || params.Weight > currentWeight {
+		usedWeight := totalSharedWeight(validLocks) - currentWeight + reservedWeight
+		if maxSharedLocks != -1 && usedWeight+params.Weight > maxSharedLocks {
+			return lockChange{}, &lockConflict{
+				reason: conflictCapacity,
+				attrs: []slog.Attr{slog.Int("weight", params.Weight), slog.Int("used_weight", usedWeight),
+					slog.Int("max_shared_locks", maxSharedLocks)},
+			}
+		}
+	}
+
+	// 4. SLock 추가 또는 갱신
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+	owner := newOwner(params.Owner, params.Metadata, now)
+	acquireEvent := AuditEventAcquire
+	if alreadyHasLock {
+		acquireEvent = AuditEventRefresh
+		for i := range validLocks {
+			if validLocks[i].LockID == params.LockID {
+				validLocks[i].ExpiresAt = expiresAt
+				validLocks[i].Weight = params.Weight
+				validLocks[i].SessionID = params.SessionID
+				validLocks[i].Owner = renewOwner(validLocks[i].Owner, owner, params.Owner)
+				break
+			}
+		}
+	} else {
+		validLocks = append(validLocks, SharedLockEntry{
+			LockID:    params.LockID,
+			ExpiresAt: expiresAt,
+			Weight:    params.Weight,
+			SessionID: params.SessionID,
+			Owner:     owner,
+		})
+	}
+
+	events := append(row.takeoverEvents(params.Name, expiredLocks, now), LockAuditEvent{
+		Name:       params.Name,
+		LockID:     params.LockID,
+		Mode:       LockModeShared,
+		Event:      acquireEvent,
+		OccurredAt: now,
+		ExpiresAt:  expiresAt,
+		Metadata:   params.Metadata,
+	})
+
+	// 5. 만료된 XLock이 남아 있으면 함께 정리 - 행의 최대 permit 수는 호출자가 지정한 값 그대로 유지
+	row.xlockID = ""
+	row.xExpiresAt = time.Time{}
+	row.xSessionID = ""
+	row.xOwner = nil
+	row.sharedLocks = validLocks
+
+	return lockChange{row: row, events: events, expiresAt: expiresAt}, nil
+}
+
+// releaseHolder removes params.LockID from the holders of the row, whether or not its lock has expired.
+// No events are returned if it held nothing.
+func releaseHolder(row lockRow, params UnlockParams, now time.Time) lockChange {
+	events := []LockAuditEvent{}
+
+	// 1. XLock 확인 및 제거
+	if row.xlockID != "" && row.xlockID == params.LockID {
+		events = append(events, LockAuditEvent{
+			Name:       params.Name,
+			LockID:     params.LockID,
+			Mode:       LockModeExclusive,
+			Event:      AuditEventRelease,
+			OccurredAt: now,
+			ExpiresAt:  row.xExpiresAt,
+		})
+		row.xlockID = ""
+		row.xExpiresAt = time.Time{}
+		row.xSessionID = ""
+		row.xOwner = nil
+	}
+
+	// 2. SLock 확인 및 제거
+	sharedLocks := []SharedLockEntry{}
+	for _, entry := range row.sharedLocks {
+		if entry.LockID != params.LockID {
+			sharedLocks = append(sharedLocks, entry)
+			continue
+		}
+
+		events = append(events, LockAuditEvent{
+			Name:       params.Name,
+			LockID:     params.LockID,
+			Mode:       LockModeShared,
+			Event:      AuditEventRelease,
+			OccurredAt: now,
+			ExpiresAt:  entry.ExpiresAt,
+		})
+	}
+	row.sharedLocks = sharedLocks
+
+	return lockChange{row: row, events: events}
+}
+
+// refreshHolder extends the live lock that params.LockID holds on the row.
+// It returns the mode of the extended lock, or "" if params.LockID holds no live lock.
+func refreshHolder(row lockRow, params RefreshParams, now time.Time) (lockChange, LockMode) {
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+
+	mode := LockMode("")
+	if row.heldExclusivelyBy(params.LockID, now) {
+		row.xExpiresAt = expiresAt
+		mode = LockModeExclusive
+	} else {
+		row.sharedLocks = slices.Clone(row.sharedLocks)
+		for i := range row.sharedLocks {
+			if row.sharedLocks[i].LockID == params.LockID && row.sharedLocks[i].ExpiresAt.After(now) {
+				row.sharedLocks[i].ExpiresAt = expiresAt
+				mode = LockModeShared
+				break
+			}
+		}
+	}
+
+	if mode == "" {
+		return lockChange{}, ""
+	}
+
+	event := LockAuditEvent{
+		Name:       params.Name,
+		LockID:     params.LockID,
+		Mode:       mode,
+		Event:      AuditEventRefresh,
+		OccurredAt: now,
+		ExpiresAt:  expiresAt,
+	}
+
+	return lockChange{row: row, events: []LockAuditEvent{event}, expiresAt: expiresAt}, mode
+}
+
+// forceRelease removes every holder of the row and reports the live ones that were removed.
+func forceRelease(row lockRow, params ForceUnlockParams, now time.Time) (lockChange, ForceUnlockResult) {
+	result := ForceUnlockResult{SharedLockIDs: []string{}}
+	events := []LockAuditEvent{}
+
+	if row.xlockID != "" && row.xExpiresAt.After(now) {
+		result.XLockID = row.xlockID
+		events = append(events, LockAuditEvent{
+			Name:       params.Name,
+			LockID:     row.xlockID,
+			Mode:       LockModeExclusive,
+			Event:      AuditEventForceUnlock,
+			OccurredAt: now,
+			ExpiresAt:  row.xExpiresAt,
+			Metadata:   params.Metadata,
+		})
+	}
+	for _, entry := range row.sharedLocks {
+		if entry.ExpiresAt.After(now) {
+			result.SharedLockIDs = append(result.SharedLockIDs, entry.LockID)
+			events = append(events, LockAuditEvent{
+				Name:       params.Name,
+				LockID:     entry.LockID,
+				Mode:       LockModeShared,
+				Event:      AuditEventForceUnlock,
+				OccurredAt: now,
+				ExpiresAt:  entry.ExpiresAt,
+				Metadata:   params.Metadata,
+			})
+		}
+	}
+	result.Released = len(events) > 0
+
+	row.xlockID = ""
+	row.xExpiresAt = time.Time{}
+	row.xSessionID = ""
+	row.xOwner = nil
+	row.sharedLocks = []SharedLockEntry{}
+
+	return lockChange{row: row, events: events}, result
+}
+
+// lockRowColumns are the columns of the lock table read by scanLockRow, in order.
+const lockRowColumns = "xlock_id, x_expires_at, x_session_id, x_owner, shared_locks, max_shared_locks"
+
+// scanLockRow reads the lockRowColumns of a lock table row that follow the columns scanned into dest.
+func scanLockRow(scanner interface{ Scan(dest ...any) error }, dest ...any) (lockRow, error) {
+	var row lockRow
+	var xlockID sql.NullString
+	var xExpiresAt sql.NullTime
+	var xSessionID sql.NullString
+	var xOwnerJSON []byte
+	var sharedLocksJSON []byte
+
+	dest = append(dest, &xlockID, &xExpiresAt, &xSessionID, &xOwnerJSON, &sharedLocksJSON, &row.maxSharedLocks)
+	if err := scanner.Scan(dest...); err != nil {
+		return lockRow{}, err
+	}
+
+	row.xlockID = xlockID.String
+	row.xExpiresAt = xExpiresAt.Time
+	row.xSessionID = xSessionID.String
+
+	if len(xOwnerJSON) > 0 {
+		if err := json.Unmarshal(xOwnerJSON, &row.xOwner); err != nil {
+			return lockRow{}, fmt.Errorf("failed to parse x_owner: %w", err)
+		}
+	}
+
+	if len(sharedLocksJSON) > 0 {
+		if err := json.Unmarshal(sharedLocksJSON, &row.sharedLocks); err != nil {
+			return lockRow{}, fmt.Errorf("failed to parse shared_locks: %w", err)
+		}
+	}
+
+	return row, nil
+}
+
+// lockRowValues returns the values of the lockRowColumns for row, storing empty fields as NULL.
+func lockRowValues(row lockRow) ([]any, error) {
+	xOwnerJSON, err := nullOwner(row.xOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal owner: %w", err)
+	}
+
+	sharedLocks := row.sharedLocks
+	if sharedLocks == nil {
+		sharedLocks = []SharedLockEntry{}
+	}
+	sharedLocksJSON, err := json.Marshal(sharedLocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal shared_locks: %w", err)
+	}
+
+	return []any{
+		sql.NullString{String: row.xlockID, Valid: row.xlockID != ""},
+		sql.NullTime{Time: row.xExpiresAt, Valid: !row.xExpiresAt.IsZero()},
+		nullSessionID(row.xSessionID),
+		xOwnerJSON,
+		sharedLocksJSON,
+		row.maxSharedLocks,
+	}, nil
+}
+
+// selectLockRowForUpdate locks the row of a lock and returns its state, or sql.ErrNoRows if there is none.
+func (c *lockClient) selectLockRowForUpdate(ctx context.Context, tx *sql.Tx, name string) (lockRow, error) {
+	selectQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE name = $1
+		FOR UPDATE;
+	`, lockRowColumns, c.options.LockTableName)
+
+	return scanLockRow(tx.QueryRowContext(ctx, selectQuery, name))
+}
+
+// insertLockRow creates the row of a lock with the given state unless it already exists,
+// and reports whether it was created.
+func (c *lockClient) insertLockRow(ctx context.Context, tx *sql.Tx, name string, row lockRow) (bool, error) {
+	values, err := lockRowValues(row)
+	if err != nil {
+		return false, err
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, %s)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb, $7)
+		ON CONFLICT (name) DO NOTHING;
+	`, c.options.LockTableName, lockRowColumns)
+
+	result, err := tx.ExecContext(ctx, insertQuery, append([]any{name}, values...)...)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// updateLockRow stores the state of a lock whose row is locked by tx.
+func (c *lockClient) updateLockRow(ctx context.Context, tx *sql.Tx, name string, row lockRow) error {
+	values, err := lockRowValues(row)
+	if err != nil {
+		return err
+	}
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET xlock_id = $2, x_expires_at = $3, x_session_id = $4, x_owner = $5::jsonb, shared_locks = $6::jsonb, max_shared_locks = $7
+		WHERE name = $1;
+	`, c.options.LockTableName)
+
+	_, err = tx.ExecContext(ctx, updateQuery, append([]any{name}, values...)...)
+
+	return err
+}
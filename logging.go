@@ -0,0 +1,112 @@
+package pglock
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Reasons reported when a lock could not be acquired
+const (
+	conflictExclusiveHolder = "exclusive_holder" // A live XLock is held by someone else
+	conflictSharedHolders   = "shared_holders"   // Live SLocks prevent an XLock
+	conflictCapacity        = "capacity"         // Not enough shared permits left
+)
+
+func (i *instrumentation) logConflict(ctx context.Context, mode LockMode, name string, lockID string, reason string, attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.String("reason", reason),
+	}, attrs...)
+
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: lock not acquired", attrs...)
+}
+
+func (i *instrumentation) logAcquired(ctx context.Context, mode LockMode, name string, lockID string, expiresAt time.Time) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: lock acquired",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.Time("expires_at", expiresAt),
+	)
+}
+
+func (i *instrumentation) logTxAcquired(ctx context.Context, mode LockMode, name string, lockID string) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: transaction-scoped lock acquired",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+	)
+}
+
+func (i *instrumentation) logRefreshed(ctx context.Context, mode LockMode, name string, lockID string, expiresAt time.Time) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: lock refreshed",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.Time("expires_at", expiresAt),
+	)
+}
+
+func (i *instrumentation) logRefreshLost(ctx context.Context, name string, lockID string) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: lock not refreshed, no longer held",
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+	)
+}
+
+func (i *instrumentation) logAttempt(ctx context.Context, mode LockMode, name string, lockID string, attempt int) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: acquisition attempt",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.Int("attempt", attempt),
+	)
+}
+
+func (i *instrumentation) logReleased(ctx context.Context, name string, lockID string, released bool) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: lock released",
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.Bool("released", released),
+	)
+}
+
+func (i *instrumentation) logForceUnlocked(ctx context.Context, name string, released bool) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: lock force-unlocked",
+		slog.String("name", name),
+		slog.Bool("released", released),
+	)
+}
+
+func (i *instrumentation) logHierarchyConflict(ctx context.Context, mode HierarchyMode, name string, lockID string, holder HierarchyHolder) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: hierarchy lock not acquired",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.String("holder", holder.LockID),
+		slog.String("holder_name", holder.Name),
+		slog.String("holder_mode", string(holder.Mode)),
+		slog.Time("holder_expires_at", holder.ExpiresAt),
+	)
+}
+
+func (i *instrumentation) logHierarchyAcquired(ctx context.Context, mode HierarchyMode, name string, lockID string, expiresAt time.Time) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: hierarchy lock acquired",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.Time("expires_at", expiresAt),
+	)
+}
+
+func (i *instrumentation) logHierarchyAttempt(ctx context.Context, mode HierarchyMode, name string, lockID string, attempt int) {
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "pglock: hierarchy lock acquisition attempt",
+		slog.String("mode", string(mode)),
+		slog.String("name", name),
+		slog.String("lock_id", lockID),
+		slog.Int("attempt", attempt),
+	)
+}
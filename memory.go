@@ -0,0 +1,1167 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type MemoryLockClientOptions struct {
+	Clock Clock // [optional] source of the current time for expiry checks. default: the system clock
+
+	EnableAudit bool // [optional] keep lock events in memory for GetLockHistory. default: false
+
+	Observer Observer     // [optional] receives an event for every lock operation. default: none
+	Logger   *slog.Logger // [optional] debug-level events for attempts, conflicts, renewals and releases. default: discarded
+
+	TracerProvider trace.TracerProvider // [optional] creates spans around lock acquisition and release. default: no tracing
+}
+
+func (options *MemoryLockClientOptions) SetDefaults() {
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
+}
+
+// NewMemoryLockClient returns a LockClient that keeps its locks in process memory.
+// It has the same XLock/SLock, TTL, MaxSharedLocks and policy semantics as the Postgres client
+// and is safe for concurrent use, so code that takes locks can be unit tested without a database.
+func NewMemoryLockClient(options MemoryLockClientOptions) LockClient {
+	options.SetDefaults()
+
+	return &memoryLockClient{
+		options: options,
+		instrumentation: instrumentation{
+			observer: options.Observer,
+			logger:   options.Logger,
+			tracer:   newTracer(options.TracerProvider),
+		},
+		locks:       map[string]*memoryLock{},
+		policies:    map[memoryPolicyKey]LockPolicy{},
+		sessions:    map[string]time.Time{},
+		barriers:    map[string]*memoryBarrier{},
+		latches:     map[string]*memoryLatch{},
+		taskResults: map[string]TaskResult{},
+		jobStatuses: map[string]JobStatus{},
+		history:     []LockAuditEvent{},
+		changed:     make(chan struct{}),
+	}
+}
+
+// memoryLock is the in-memory counterpart of a lock table row
+type memoryLock struct {
+	lockRow
+	value []byte
+}
+
+// memoryBarrier is the in-memory counterpart of a barrier table row
+type memoryBarrier struct {
+	generation   int64
+	participants []BarrierParticipant
+}
+
+// memoryLatch is the in-memory counterpart of a latch table row
+type memoryLatch struct {
+	info          LatchInfo
+	countedEvents []string
+}
+
+type memoryPolicyKey struct {
+	name   string
+	prefix bool
+}
+
+type memoryLockClient struct {
+	options MemoryLockClientOptions
+
+	instrumentation
+
+	mu          sync.Mutex
+	locks       map[string]*memoryLock
+	policies    map[memoryPolicyKey]LockPolicy
+	sessions    map[string]time.Time // expiration time of the open sessions
+	hierarchy   []HierarchyHolder    // locks on the nodes of hierarchical lock names
+	barriers    map[string]*memoryBarrier
+	latches     map[string]*memoryLatch
+	taskResults map[string]TaskResult
+	jobStatuses map[string]JobStatus
+	history     []LockAuditEvent
+	lastEventID int64
+	changed     chan struct{} // closed and replaced whenever holders are released, to wake up waiters
+}
+
+var (
+	_ BarrierStore    = (*memoryLockClient)(nil)
+	_ LatchStore      = (*memoryLockClient)(nil)
+	_ TaskResultStore = (*memoryLockClient)(nil)
+	_ JobStatusStore  = (*memoryLockClient)(nil)
+)
+
+func (c *memoryLockClient) Initialize() error {
+	return nil
+}
+
+func (c *memoryLockClient) Connect() error {
+	return nil
+}
+
+func (c *memoryLockClient) SetupTables() error {
+	return nil
+}
+
+// notifyChanged wakes up XLock and SLock calls waiting for the lock state to change. c.mu must be held.
+func (c *memoryLockClient) notifyChanged() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// changes returns a channel that is closed on the next state change.
+func (c *memoryLockClient) changes() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.changed
+}
+
+// waitForRetry blocks until the state changes, the retry interval passes or ctx is done.
+func waitForRetry(ctx context.Context, changed <-chan struct{}, interval time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+		return nil
+	case <-time.After(interval):
+		return nil
+	}
+}
+
+// findLockPolicy looks up the effective policy for a lock name. c.mu must be held.
+func (c *memoryLockClient) findLockPolicy(name string) (LockPolicy, bool) {
+	// 정확히 일치하는 정책 우선, 그 다음 가장 긴 prefix 정책
+	if policy, ok := c.policies[memoryPolicyKey{name: name}]; ok {
+		return policy, true
+	}
+
+	var found LockPolicy
+	hasPolicy := false
+	for key, policy := range c.policies {
+		if !key.prefix || !strings.HasPrefix(name, key.name) {
+			continue
+		}
+		if !hasPolicy || len(key.name) > len(found.Name) {
+			found = policy
+			hasPolicy = true
+		}
+	}
+
+	return found, hasPolicy
+}
+
+// recordAuditEvents keeps the events for GetLockHistory. It does nothing unless audit is enabled. c.mu must be held.
+func (c *memoryLockClient) recordAuditEvents(events ...LockAuditEvent) {
+	if !c.options.EnableAudit {
+		return
+	}
+
+	for _, event := range events {
+		c.lastEventID++
+		event.ID = c.lastEventID
+		if hasJSONValue(event.Metadata) {
+			event.Metadata = slices.Clone(event.Metadata)
+		} else {
+			event.Metadata = nil
+		}
+
+		c.history = append(c.history, event)
+	}
+}
+
+// TryXLock attempts to acquire a distributed lock.
+func (c *memoryLockClient) TryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
+	startedAt := time.Now()
+
+	result, err := c.tracedTryXLock(ctx, params)
+
+	c.observe(LockEvent{
+		Operation: OperationTryXLock,
+		Name:      params.Name,
+		Mode:      LockModeExclusive,
+		Outcome:   outcomeOf(err, result.Acquired, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *memoryLockClient) tracedTryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
+	ctx, span := c.startSpan(ctx, "pglock.TryXLock", LockModeExclusive, params.Name, params.LockID)
+
+	result, err := c.tryXLock(ctx, params)
+
+	span.SetAttributes(AttributeAcquired.Bool(result.Acquired))
+	endSpan(span, err)
+
+	return result, err
+}
+
+func (c *memoryLockClient) tryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return TryXLockResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	policy, _ := c.findLockPolicy(params.Name)
+	params.TTLSeconds = policy.resolveTTL(params.TTLSeconds)
+
+	now := c.options.Clock.Now()
+
+	// 세션에 묶인 요청이면 세션이 살아 있어야 함
+	if params.SessionID != "" && !c.sessionAlive(params.SessionID, now) {
+		return TryXLockResult{}, ErrSessionLost
+	}
+
+	lock, exists := c.locks[params.Name]
+	if !exists {
+		lock = &memoryLock{lockRow: lockRow{maxSharedLocks: -1}}
+	}
+
+	// 종료된 세션에 묶인 보유자는 TTL과 무관하게 만료된 것으로 취급
+	c.expireDeadSessionHolders(lock, now)
+
+	change, conflict := acquireXLock(lock.lockRow, params, now)
+	if conflict != nil {
+		c.logConflict(ctx, LockModeExclusive, params.Name, params.LockID, conflict.reason, conflict.attrs...)
+		return TryXLockResult{Acquired: false}, nil
+	}
+
+	lock.lockRow = change.row
+	c.locks[params.Name] = lock
+
+	c.recordAuditEvents(change.events...)
+	c.logAcquired(ctx, LockModeExclusive, params.Name, params.LockID, change.expiresAt)
+
+	return TryXLockResult{ExpiresAt: change.expiresAt, Acquired: true}, nil
+}
+
+// XLock continuously attempts to acquire a distributed lock until successful.
+func (c *memoryLockClient) XLock(ctx context.Context, params XLockParams) (XLockResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.XLock", LockModeExclusive, params.Name, params.LockID)
+
+	result, attempts, err := c.xLock(ctx, params)
+
+	endWaitSpan(span, attempts, time.Since(startedAt), err == nil, err)
+	c.observe(LockEvent{
+		Operation: OperationXLock,
+		Name:      params.Name,
+		Mode:      LockModeExclusive,
+		Outcome:   outcomeOf(err, true, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  attempts,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *memoryLockClient) xLock(ctx context.Context, params XLockParams) (XLockResult, int, error) {
+	if params.IntervalDuration <= 0 {
+		params.IntervalDuration = DefaultRetryInterval
+	}
+
+	attempts := 0
+	for {
+		attempts++
+		c.logAttempt(ctx, LockModeExclusive, params.Name, params.LockID, attempts)
+
+		// 시도 전에 구독해야 시도와 대기 사이의 해제를 놓치지 않음
+		changed := c.changes()
+
+		result, err := c.tracedTryXLock(ctx, TryXLockParams{
+			Name:       params.Name,
+			LockID:     params.LockID,
+			TTLSeconds: params.TTLSeconds,
+			SessionID:  params.SessionID,
+			Metadata:   params.Metadata,
+			Owner:      params.Owner,
+		})
+		if err != nil {
+			return XLockResult{}, attempts, err
+		}
+		if result.Acquired {
+			return XLockResult{ExpiresAt: result.ExpiresAt}, attempts, nil
+		}
+
+		if err := waitForRetry(ctx, changed, params.IntervalDuration); err != nil {
+			return XLockResult{}, attempts, err
+		}
+	}
+}
+
+// TrySLock attempts to acquire a shared lock (non-blocking).
+func (c *memoryLockClient) TrySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
+	startedAt := time.Now()
+
+	result, err := c.tracedTrySLock(ctx, params)
+
+	c.observe(LockEvent{
+		Operation: OperationTrySLock,
+		Name:      params.Name,
+		Mode:      LockModeShared,
+		Outcome:   outcomeOf(err, result.Acquired, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *memoryLockClient) tracedTrySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
+	ctx, span := c.startSpan(ctx, "pglock.TrySLock", LockModeShared, params.Name, params.LockID)
+
+	result, err := c.trySLock(ctx, params)
+
+	span.SetAttributes(AttributeAcquired.Bool(result.Acquired))
+	endSpan(span, err)
+
+	return result, err
+}
+
+func (c *memoryLockClient) trySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return TrySLockResult{}, err
+	}
+
+	// Weight가 0 이하면 기본값 1 사용
+	if params.Weight <= 0 {
+		params.Weight = 1
+	}
+
+	// MaxSharedLocks가 0이면 제한 없음(-1)으로 취급
+	if params.MaxSharedLocks == 0 {
+		params.MaxSharedLocks = -1
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 정책이 있으면 누가 먼저 만들었는지와 무관하게 정책의 제한을 적용
+	policy, _ := c.findLockPolicy(params.Name)
+	params.TTLSeconds = policy.resolveTTL(params.TTLSeconds)
+
+	now := c.options.Clock.Now()
+
+	// 세션에 묶인 요청이면 세션이 살아 있어야 함
+	if params.SessionID != "" && !c.sessionAlive(params.SessionID, now) {
+		return TrySLockResult{}, ErrSessionLost
+	}
+
+	lock, exists := c.locks[params.Name]
+	if !exists {
+		lock = &memoryLock{lockRow: lockRow{maxSharedLocks: params.MaxSharedLocks}}
+	}
+
+	// 종료된 세션에 묶인 보유자는 TTL과 무관하게 만료된 것으로 취급
+	c.expireDeadSessionHolders(lock, now)
+
+	change, conflict := acquireSLock(lock.lockRow, params, policy.resolveMaxSharedLocks(lock.maxSharedLocks), 0, now)
+	if conflict != nil {
+		c.logConflict(ctx, LockModeShared, params.Name, params.LockID, conflict.reason, conflict.attrs...)
+		return TrySLockResult{Acquired: false}, nil
+	}
+
+	lock.lockRow = change.row
+	c.locks[params.Name] = lock
+
+	c.recordAuditEvents(change.events...)
+	c.logAcquired(ctx, LockModeShared, params.Name, params.LockID, change.expiresAt)
+
+	return TrySLockResult{ExpiresAt: change.expiresAt, Acquired: true}, nil
+}
+
+// SLock continuously attempts to acquire a shared lock until successful.
+func (c *memoryLockClient) SLock(ctx context.Context, params SLockParams) (SLockResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.SLock", LockModeShared, params.Name, params.LockID)
+
+	result, attempts, err := c.sLock(ctx, params)
+
+	endWaitSpan(span, attempts, time.Since(startedAt), err == nil, err)
+	c.observe(LockEvent{
+		Operation: OperationSLock,
+		Name:      params.Name,
+		Mode:      LockModeShared,
+		Outcome:   outcomeOf(err, true, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  attempts,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *memoryLockClient) sLock(ctx context.Context, params SLockParams) (SLockResult, int, error) {
+	if params.IntervalDuration <= 0 {
+		params.IntervalDuration = DefaultRetryInterval
+	}
+
+	attempts := 0
+	for {
+		attempts++
+		c.logAttempt(ctx, LockModeShared, params.Name, params.LockID, attempts)
+
+		changed := c.changes()
+
+		result, err := c.tracedTrySLock(ctx, TrySLockParams{
+			Name:           params.Name,
+			LockID:         params.LockID,
+			TTLSeconds:     params.TTLSeconds,
+			MaxSharedLocks: params.MaxSharedLocks,
+			Weight:         params.Weight,
+			SessionID:      params.SessionID,
+			Metadata:       params.Metadata,
+			Owner:          params.Owner,
+		})
+		if err != nil {
+			return SLockResult{}, attempts, err
+		}
+		if result.Acquired {
+			return SLockResult{ExpiresAt: result.ExpiresAt}, attempts, nil
+		}
+
+		if err := waitForRetry(ctx, changed, params.IntervalDuration); err != nil {
+			return SLockResult{}, attempts, err
+		}
+	}
+}
+
+// TryXLockTx returns ErrNotSupported: transaction-scoped locks need a PostgreSQL transaction.
+func (c *memoryLockClient) TryXLockTx(ctx context.Context, tx *sql.Tx, params TryXLockTxParams) (TryXLockTxResult, error) {
+	return TryXLockTxResult{}, ErrNotSupported
+}
+
+// XLockTx returns ErrNotSupported: transaction-scoped locks need a PostgreSQL transaction.
+func (c *memoryLockClient) XLockTx(ctx context.Context, tx *sql.Tx, params XLockTxParams) (XLockTxResult, error) {
+	return XLockTxResult{}, ErrNotSupported
+}
+
+// TrySLockTx returns ErrNotSupported: transaction-scoped locks need a PostgreSQL transaction.
+func (c *memoryLockClient) TrySLockTx(ctx context.Context, tx *sql.Tx, params TrySLockTxParams) (TrySLockTxResult, error) {
+	return TrySLockTxResult{}, ErrNotSupported
+}
+
+// Unlock releases the lock if we still own it (either XLock or SLock).
+func (c *memoryLockClient) Unlock(ctx context.Context, params UnlockParams) (UnlockResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.Unlock", "", params.Name, params.LockID)
+
+	result, err := c.unlock(ctx, params)
+
+	span.SetAttributes(AttributeReleased.Bool(result.Released))
+	endSpan(span, err)
+
+	c.observe(LockEvent{
+		Operation: OperationUnlock,
+		Name:      params.Name,
+		Outcome:   outcomeOf(err, result.Released, OutcomeReleased, OutcomeNotHeld),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *memoryLockClient) unlock(ctx context.Context, params UnlockParams) (UnlockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return UnlockResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, exists := c.locks[params.Name]
+	if !exists {
+		return UnlockResult{Released: false}, nil
+	}
+
+	change := releaseHolder(lock.lockRow, params, c.options.Clock.Now())
+	lock.lockRow = change.row
+	released := len(change.events) > 0
+
+	c.recordAuditEvents(change.events...)
+	if released {
+		c.notifyChanged()
+	}
+	c.logReleased(ctx, params.Name, params.LockID, released)
+
+	return UnlockResult{Released: released}, nil
+}
+
+// Refresh extends the TTL of a lock (either XLock or SLock) that we still hold.
+func (c *memoryLockClient) Refresh(ctx context.Context, params RefreshParams) (RefreshResult, error) {
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.Refresh", "", params.Name, params.LockID)
+
+	result, err := c.refresh(ctx, params)
+
+	span.SetAttributes(AttributeRefreshed.Bool(result.Refreshed))
+	endSpan(span, err)
+
+	c.observe(LockEvent{
+		Operation: OperationRefresh,
+		Name:      params.Name,
+		Outcome:   outcomeOf(err, result.Refreshed, OutcomeRefreshed, OutcomeLost),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return result, err
+}
+
+func (c *memoryLockClient) refresh(ctx context.Context, params RefreshParams) (RefreshResult, error) {
+	if err := ctx.Err(); err != nil {
+		return RefreshResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	policy, _ := c.findLockPolicy(params.Name)
+	params.TTLSeconds = policy.resolveTTL(params.TTLSeconds)
+
+	lock, exists := c.locks[params.Name]
+	if !exists {
+		c.logRefreshLost(ctx, params.Name, params.LockID)
+		return RefreshResult{Refreshed: false}, nil
+	}
+
+	// 종료된 세션에 묶인 락은 연장하지 않음
+	now := c.options.Clock.Now()
+	c.expireDeadSessionHolders(lock, now)
+
+	change, mode := refreshHolder(lock.lockRow, params, now)
+	if mode == "" {
+		c.logRefreshLost(ctx, params.Name, params.LockID)
+		return RefreshResult{Refreshed: false}, nil
+	}
+	lock.lockRow = change.row
+
+	c.recordAuditEvents(change.events...)
+	c.logRefreshed(ctx, mode, params.Name, params.LockID, change.expiresAt)
+
+	return RefreshResult{ExpiresAt: change.expiresAt, Refreshed: true}, nil
+}
+
+// GetValue returns the value of a lock. It fails with ErrNotLockHolder unless LockID holds the XLock.
+func (c *memoryLockClient) GetValue(ctx context.Context, params GetValueParams) (GetValueResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, err := c.heldLock(params.Name, params.LockID)
+	if err != nil {
+		return GetValueResult{}, err
+	}
+
+	return GetValueResult{Value: slices.Clone(lock.value), Found: lock.value != nil}, nil
+}
+
+// SetValue replaces the value of a lock. It fails with ErrNotLockHolder unless LockID holds the XLock.
+func (c *memoryLockClient) SetValue(ctx context.Context, params SetValueParams) (SetValueResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, err := c.heldLock(params.Name, params.LockID)
+	if err != nil {
+		return SetValueResult{}, err
+	}
+	lock.value = slices.Clone(params.Value)
+
+	return SetValueResult{}, nil
+}
+
+// heldLock returns the lock if lockID is its live XLock holder, or ErrNotLockHolder. c.mu must be held.
+func (c *memoryLockClient) heldLock(name string, lockID string) (*memoryLock, error) {
+	lock, exists := c.locks[name]
+	if !exists {
+		return nil, ErrNotLockHolder
+	}
+
+	// 종료된 세션에 묶인 보유자는 보유하지 않은 것으로 취급
+	now := c.options.Clock.Now()
+	c.expireDeadSessionHolders(lock, now)
+
+	if !lock.heldExclusivelyBy(lockID, now) {
+		return nil, ErrNotLockHolder
+	}
+
+	return lock, nil
+}
+
+// ForceUnlock removes every holder of a lock regardless of who owns it.
+func (c *memoryLockClient) ForceUnlock(ctx context.Context, params ForceUnlockParams) (ForceUnlockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ForceUnlockResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, exists := c.locks[params.Name]
+	if !exists {
+		return ForceUnlockResult{SharedLockIDs: []string{}}, nil
+	}
+
+	now := c.options.Clock.Now()
+	c.expireDeadSessionHolders(lock, now)
+
+	change, result := forceRelease(lock.lockRow, params, now)
+	lock.lockRow = change.row
+
+	c.recordAuditEvents(change.events...)
+	c.notifyChanged()
+	c.logForceUnlocked(ctx, params.Name, result.Released)
+
+	return result, nil
+}
+
+// DescribeLock returns the current holders of a lock. Expired holders are omitted.
+func (c *memoryLockClient) DescribeLock(ctx context.Context, params DescribeLockParams) (DescribeLockResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, exists := c.locks[params.Name]
+	if !exists {
+		return DescribeLockResult{Found: false}, nil
+	}
+
+	now := c.options.Clock.Now()
+	c.expireDeadSessionHolders(lock, now)
+
+	return DescribeLockResult{Lock: lock.lockInfo(params.Name, now), Found: true}, nil
+}
+
+// ListLocks returns the locks ordered by name.
+// By default only locks with at least one live holder are returned.
+func (c *memoryLockClient) ListLocks(ctx context.Context, params ListLocksParams) (ListLocksResult, error) {
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := []string{}
+	for name := range c.locks {
+		if strings.HasPrefix(name, params.Prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	now := c.options.Clock.Now()
+	locks := []LockInfo{}
+	for _, name := range names {
+		if len(locks) >= params.Limit {
+			break
+		}
+
+		c.expireDeadSessionHolders(c.locks[name], now)
+		lock := c.locks[name].lockInfo(name, now)
+		if !params.IncludeFree && lock.XLockID == "" && len(lock.SharedLocks) == 0 {
+			continue
+		}
+
+		locks = append(locks, lock)
+	}
+
+	return ListLocksResult{Locks: locks}, nil
+}
+
+// TryHierarchyLock attempts to lock Name in Mode together with the intention locks on its ancestors.
+// Calling it again with the same Name and LockID renews the lock (and may change its mode).
+func (c *memoryLockClient) TryHierarchyLock(ctx context.Context, params TryHierarchyLockParams) (TryHierarchyLockResult, error) {
+	if !params.Mode.valid() {
+		return TryHierarchyLockResult{}, fmt.Errorf("pglock: unknown hierarchy mode %q", params.Mode)
+	}
+	if !validHierarchyName(params.Name) {
+		return TryHierarchyLockResult{}, fmt.Errorf("pglock: hierarchy lock name %q has an empty path segment", params.Name)
+	}
+	if err := ctx.Err(); err != nil {
+		return TryHierarchyLockResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	requests := hierarchyRequests(params.Name, params.Mode)
+	now := c.options.Clock.Now()
+
+	// 경로의 각 노드에서 다른 보유자와 호환되는지 확인
+	for _, request := range requests {
+		for _, holder := range c.hierarchy {
+			if holder.Name != request.name || holder.LockID == params.LockID || !holder.ExpiresAt.After(now) {
+				continue
+			}
+			if !request.mode.Compatible(holder.Mode) {
+				c.logHierarchyConflict(ctx, params.Mode, params.Name, params.LockID, holder)
+				return TryHierarchyLockResult{Acquired: false, Conflict: holder}, nil
+			}
+		}
+	}
+
+	// 같은 보유자의 이전 기록과 만료된 보유자 정리
+	holders := []HierarchyHolder{}
+	for _, holder := range c.hierarchy {
+		if (holder.LockID == params.LockID && holder.Target == params.Name) || !holder.ExpiresAt.After(now) {
+			continue
+		}
+		holders = append(holders, holder)
+	}
+
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+	for _, request := range requests {
+		holders = append(holders, HierarchyHolder{
+			Name:      request.name,
+			LockID:    params.LockID,
+			Target:    params.Name,
+			Mode:      request.mode,
+			ExpiresAt: expiresAt,
+		})
+	}
+	c.hierarchy = holders
+
+	c.logHierarchyAcquired(ctx, params.Mode, params.Name, params.LockID, expiresAt)
+
+	return TryHierarchyLockResult{ExpiresAt: expiresAt, Acquired: true}, nil
+}
+
+// HierarchyLock continuously attempts to acquire a hierarchical lock until successful.
+func (c *memoryLockClient) HierarchyLock(ctx context.Context, params HierarchyLockParams) (HierarchyLockResult, error) {
+	return hierarchyLock(ctx, c, &c.instrumentation, params)
+}
+
+// HierarchyUnlock releases a hierarchical lock and the intention locks taken for it.
+func (c *memoryLockClient) HierarchyUnlock(ctx context.Context, params HierarchyUnlockParams) (HierarchyUnlockResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.options.Clock.Now()
+	released := false
+	holders := []HierarchyHolder{}
+	for _, holder := range c.hierarchy {
+		if holder.LockID != params.LockID || holder.Target != params.Name {
+			holders = append(holders, holder)
+			continue
+		}
+		if holder.Name == params.Name && holder.ExpiresAt.After(now) {
+			released = true
+		}
+	}
+	c.hierarchy = holders
+
+	c.notifyChanged()
+	c.logReleased(ctx, params.Name, params.LockID, released)
+
+	return HierarchyUnlockResult{Released: released}, nil
+}
+
+// ArriveBarrier registers the participant at the barrier, or checks on it while waiting.
+// The barrier opens once Parties live participants have arrived; call again with the returned
+// Generation until Released (which also renews the participant's TTL).
+func (c *memoryLockClient) ArriveBarrier(ctx context.Context, params ArriveBarrierParams) (ArriveBarrierResult, error) {
+	if params.Parties <= 0 {
+		return ArriveBarrierResult{}, errInvalidBarrierParties
+	}
+	if err := ctx.Err(); err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	barrier, ok := c.barriers[params.Name]
+	if !ok {
+		barrier = &memoryBarrier{generation: 1, participants: []BarrierParticipant{}}
+		c.barriers[params.Name] = barrier
+	}
+
+	var result ArriveBarrierResult
+	barrier.generation, barrier.participants, result = arriveBarrier(barrier.generation, barrier.participants, params, c.options.Clock.Now())
+
+	return result, nil
+}
+
+// LeaveBarrier withdraws a participant that gave up waiting, so that it no longer counts as arrived.
+func (c *memoryLockClient) LeaveBarrier(ctx context.Context, params LeaveBarrierParams) (LeaveBarrierResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	barrier, ok := c.barriers[params.Name]
+	if !ok {
+		return LeaveBarrierResult{Left: false}, nil
+	}
+
+	var left bool
+	barrier.participants, left = leaveBarrier(barrier.generation, barrier.participants, params, c.options.Clock.Now())
+
+	return LeaveBarrierResult{Left: left}, nil
+}
+
+// CreateLatch creates a latch with Count, unless a latch with the same name already exists.
+func (c *memoryLockClient) CreateLatch(ctx context.Context, params CreateLatchParams) (CreateLatchResult, error) {
+	if params.Count < 0 {
+		return CreateLatchResult{}, errInvalidLatchCount
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if latch, ok := c.latches[params.Name]; ok {
+		return CreateLatchResult{Latch: latch.info, Created: false}, nil
+	}
+
+	latch := &memoryLatch{info: LatchInfo{Name: params.Name, Count: params.Count, Remaining: params.Count}}
+	c.latches[params.Name] = latch
+
+	return CreateLatchResult{Latch: latch.info, Created: true}, nil
+}
+
+// CountDown decrements the count of a latch. A latch that is already open stays at zero.
+// A count down with an EventID that was already counted leaves the count as is.
+func (c *memoryLockClient) CountDown(ctx context.Context, params CountDownParams) (CountDownResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	latch, ok := c.latches[params.Name]
+	if !ok {
+		return CountDownResult{Found: false}, nil
+	}
+	if params.EventID != "" && slices.Contains(latch.countedEvents, params.EventID) {
+		return CountDownResult{Remaining: latch.info.Remaining, Found: true, Duplicate: true}, nil
+	}
+	if latch.info.Remaining > 0 {
+		latch.info.Remaining--
+		if params.EventID != "" {
+			latch.countedEvents = append(latch.countedEvents, params.EventID)
+		}
+	}
+
+	return CountDownResult{Remaining: latch.info.Remaining, Found: true}, nil
+}
+
+// DescribeLatch returns the state of a latch.
+func (c *memoryLockClient) DescribeLatch(ctx context.Context, params DescribeLatchParams) (DescribeLatchResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	latch, ok := c.latches[params.Name]
+	if !ok {
+		return DescribeLatchResult{Found: false}, nil
+	}
+
+	return DescribeLatchResult{Latch: latch.info, Found: true}, nil
+}
+
+// StoreTaskResult records the outcome of a task, unless the key already has a live one.
+// The first outcome wins until it expires, so a recorded result never changes while it is live.
+func (c *memoryLockClient) StoreTaskResult(ctx context.Context, params StoreTaskResultParams) (StoreTaskResultResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.options.Clock.Now()
+	if result, ok := c.liveTaskResult(params.Key, now); ok {
+		result.Value = slices.Clone(result.Value)
+		return StoreTaskResultResult{Result: result, Stored: false}, nil
+	}
+
+	result := TaskResult{
+		Key:         params.Key,
+		Value:       slices.Clone(params.Value),
+		Failed:      params.Failed,
+		Error:       params.Error,
+		CompletedAt: now,
+		ExpiresAt:   taskResultExpiresAt(now, params.TTLSeconds),
+	}
+	c.taskResults[params.Key] = result
+
+	result.Value = slices.Clone(result.Value)
+	return StoreTaskResultResult{Result: result, Stored: true}, nil
+}
+
+// GetTaskResult returns the recorded outcome of a task, unless it has expired.
+func (c *memoryLockClient) GetTaskResult(ctx context.Context, params GetTaskResultParams) (GetTaskResultResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.liveTaskResult(params.Key, c.options.Clock.Now())
+	if !ok {
+		return GetTaskResultResult{Found: false}, nil
+	}
+	result.Value = slices.Clone(result.Value)
+
+	return GetTaskResultResult{Result: result, Found: true}, nil
+}
+
+// DeleteTaskResult discards the recorded outcome of a task, so that RunOnce runs it again.
+func (c *memoryLockClient) DeleteTaskResult(ctx context.Context, params DeleteTaskResultParams) (DeleteTaskResultResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.taskResults[params.Key]
+	delete(c.taskResults, params.Key)
+
+	return DeleteTaskResultResult{Deleted: ok}, nil
+}
+
+// PruneTaskResults deletes the expired outcomes.
+func (c *memoryLockClient) PruneTaskResults(ctx context.Context, params PruneTaskResultsParams) (PruneTaskResultsResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.options.Clock.Now()
+	var deleted int64
+	for key := range c.taskResults {
+		if _, ok := c.liveTaskResult(key, now); !ok {
+			delete(c.taskResults, key)
+			deleted++
+		}
+	}
+
+	return PruneTaskResultsResult{Deleted: deleted}, nil
+}
+
+// liveTaskResult returns the outcome recorded for key if it has not expired at now. c.mu must be held.
+func (c *memoryLockClient) liveTaskResult(key string, now time.Time) (TaskResult, bool) {
+	result, ok := c.taskResults[key]
+	if !ok || (!result.ExpiresAt.IsZero() && !result.ExpiresAt.After(now)) {
+		return TaskResult{}, false
+	}
+
+	return result, true
+}
+
+// SaveJobStatus records the last run of a job. A status for an earlier tick than the recorded one is ignored.
+func (c *memoryLockClient) SaveJobStatus(ctx context.Context, params SaveJobStatusParams) (SaveJobStatusResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, ok := c.jobStatuses[params.Status.Name]; ok && previous.ScheduledAt.After(params.Status.ScheduledAt) {
+		return SaveJobStatusResult{Saved: false}, nil
+	}
+	c.jobStatuses[params.Status.Name] = params.Status
+
+	return SaveJobStatusResult{Saved: true}, nil
+}
+
+// GetJobStatus returns the last run of a job.
+func (c *memoryLockClient) GetJobStatus(ctx context.Context, params GetJobStatusParams) (GetJobStatusResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status, ok := c.jobStatuses[params.Name]
+	if !ok {
+		return GetJobStatusResult{Found: false}, nil
+	}
+
+	return GetJobStatusResult{Status: status, Found: true}, nil
+}
+
+// OpenSession registers a new session. Keep it alive with RefreshSession (or KeepSessionAlive).
+func (c *memoryLockClient) OpenSession(ctx context.Context, params OpenSessionParams) (OpenSessionResult, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return OpenSessionResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.options.Clock.Now()
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+
+	// 만료된 세션 정리 - 없는 세션도 종료된 것으로 취급하므로 안전
+	for id, sessionExpiresAt := range c.sessions {
+		if !sessionExpiresAt.After(now) {
+			delete(c.sessions, id)
+		}
+	}
+	c.sessions[sessionID] = expiresAt
+
+	return OpenSessionResult{SessionID: sessionID, ExpiresAt: expiresAt}, nil
+}
+
+// RefreshSession extends a session that is still alive. An expired session cannot be revived.
+func (c *memoryLockClient) RefreshSession(ctx context.Context, params RefreshSessionParams) (RefreshSessionResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.options.Clock.Now()
+	if !c.sessionAlive(params.SessionID, now) {
+		return RefreshSessionResult{Refreshed: false}, nil
+	}
+
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+	c.sessions[params.SessionID] = expiresAt
+
+	return RefreshSessionResult{ExpiresAt: expiresAt, Refreshed: true}, nil
+}
+
+// CloseSession ends a session, releasing every lock bound to it.
+func (c *memoryLockClient) CloseSession(ctx context.Context, params CloseSessionParams) (CloseSessionResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	closed := c.sessionAlive(params.SessionID, c.options.Clock.Now())
+	delete(c.sessions, params.SessionID)
+
+	// 세션에 묶인 락이 풀렸으므로 대기 중인 호출을 깨움
+	c.notifyChanged()
+
+	return CloseSessionResult{Closed: closed}, nil
+}
+
+// sessionAlive reports whether a session is open and has not expired at now. c.mu must be held.
+func (c *memoryLockClient) sessionAlive(sessionID string, now time.Time) bool {
+	expiresAt, ok := c.sessions[sessionID]
+
+	return ok && expiresAt.After(now)
+}
+
+// expireDeadSessionHolders marks the holders whose session is no longer alive as expired at now. c.mu must be held.
+func (c *memoryLockClient) expireDeadSessionHolders(lock *memoryLock, now time.Time) {
+	lock.lockRow.expireDeadSessionHolders(func(sessionID string) bool { return c.sessionAlive(sessionID, now) }, now)
+}
+
+// ListNamespaces returns the namespaces of the locks with their lock counts.
+func (c *memoryLockClient) ListNamespaces(ctx context.Context, params ListNamespacesParams) (ListNamespacesResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.options.Clock.Now()
+	counts := map[string]*NamespaceInfo{}
+	for name, lock := range c.locks {
+		c.expireDeadSessionHolders(lock, now)
+		countNamespaces(counts, lock.lockInfo(name, now))
+	}
+
+	return ListNamespacesResult{Namespaces: sortedNamespaces(counts)}, nil
+}
+
+// SetLockPolicy creates or replaces the policy for a lock name or prefix.
+func (c *memoryLockClient) SetLockPolicy(ctx context.Context, params SetLockPolicyParams) (SetLockPolicyResult, error) {
+	if err := validateLockPolicy(params); err != nil {
+		return SetLockPolicyResult{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	policy := LockPolicy(params)
+	c.policies[memoryPolicyKey{name: params.Name, prefix: params.Prefix}] = policy
+
+	// 허용량이 늘었을 수 있으므로 대기 중인 호출을 깨움
+	c.notifyChanged()
+
+	return SetLockPolicyResult{Policy: policy}, nil
+}
+
+// GetLockPolicy returns the policy that acquire calls apply to the given lock name.
+func (c *memoryLockClient) GetLockPolicy(ctx context.Context, params GetLockPolicyParams) (GetLockPolicyResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	policy, found := c.findLockPolicy(params.Name)
+
+	return GetLockPolicyResult{Policy: policy, Found: found}, nil
+}
+
+// DeleteLockPolicy removes the policy for a lock name or prefix.
+func (c *memoryLockClient) DeleteLockPolicy(ctx context.Context, params DeleteLockPolicyParams) (DeleteLockPolicyResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := memoryPolicyKey{name: params.Name, prefix: params.Prefix}
+	if _, exists := c.policies[key]; !exists {
+		return DeleteLockPolicyResult{Deleted: false}, nil
+	}
+
+	delete(c.policies, key)
+	c.notifyChanged()
+
+	return DeleteLockPolicyResult{Deleted: true}, nil
+}
+
+// GetLockHistory returns the recorded events of a lock in a time range.
+func (c *memoryLockClient) GetLockHistory(ctx context.Context, params GetLockHistoryParams) (GetLockHistoryResult, error) {
+	if !c.options.EnableAudit {
+		return GetLockHistoryResult{}, ErrAuditDisabled
+	}
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events := []LockAuditEvent{}
+	for _, event := range c.history {
+		if len(events) >= params.Limit {
+			break
+		}
+		if event.Name != params.Name {
+			continue
+		}
+		if !params.From.IsZero() && event.OccurredAt.Before(params.From) {
+			continue
+		}
+		if !params.To.IsZero() && !event.OccurredAt.Before(params.To) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return GetLockHistoryResult{Events: events}, nil
+}
+
+// PruneLockHistory deletes recorded events older than the retention period.
+func (c *memoryLockClient) PruneLockHistory(ctx context.Context, params PruneLockHistoryParams) (PruneLockHistoryResult, error) {
+	if !c.options.EnableAudit {
+		return PruneLockHistoryResult{}, ErrAuditDisabled
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	threshold := c.options.Clock.Now().Add(-params.Retention)
+	kept := []LockAuditEvent{}
+	for _, event := range c.history {
+		if !event.OccurredAt.Before(threshold) {
+			kept = append(kept, event)
+		}
+	}
+
+	deleted := int64(len(c.history) - len(kept))
+	c.history = kept
+
+	return PruneLockHistoryResult{Deleted: deleted}, nil
+}
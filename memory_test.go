@@ -0,0 +1,200 @@
+package pglock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryLockClient_ExpiryWithManualClock tests that locks expire when the clock is advanced past their TTL
+func TestMemoryLockClient_ExpiryWithManualClock(t *testing.T) {
+	ctx := context.Background()
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := NewMemoryLockClient(MemoryLockClientOptions{Clock: clock})
+
+	// 1. XLock 획득 - 만료 시각은 주입한 시계 기준
+	result1, err := client.TryXLock(ctx, TryXLockParams{Name: "test_memory_expiry", LockID: "lock_1", TTLSeconds: 10})
+	require.NoError(t, err)
+	require.True(t, result1.Acquired)
+	assert.Equal(t, clock.Now().Add(10*time.Second), result1.ExpiresAt)
+
+	// 2. 만료 전에는 다른 보유자가 획득할 수 없음
+	clock.Advance(9 * time.Second)
+	result2, err := client.TryXLock(ctx, TryXLockParams{Name: "test_memory_expiry", LockID: "lock_2", TTLSeconds: 10})
+	require.NoError(t, err)
+	assert.False(t, result2.Acquired)
+
+	// 3. TTL이 지나면 다른 보유자가 획득 가능
+	clock.Advance(time.Second)
+	result3, err := client.TryXLock(ctx, TryXLockParams{Name: "test_memory_expiry", LockID: "lock_2", TTLSeconds: 10})
+	require.NoError(t, err)
+	assert.True(t, result3.Acquired)
+
+	// 4. 만료된 보유자는 갱신할 수 없음
+	refreshResult, err := client.Refresh(ctx, RefreshParams{Name: "test_memory_expiry", LockID: "lock_1", TTLSeconds: 10})
+	require.NoError(t, err)
+	assert.False(t, refreshResult.Refreshed)
+}
+
+// TestMemoryLockClient_SharedCapacity tests weighted shared locks against MaxSharedLocks and XLock conflicts
+func TestMemoryLockClient_SharedCapacity(t *testing.T) {
+	ctx := context.Background()
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := NewMemoryLockClient(MemoryLockClientOptions{Clock: clock})
+
+	// 1. 10개 중 5 + 4 permit 획득
+	result1, err := client.TrySLock(ctx, TrySLockParams{Name: "test_memory_shared", LockID: "big", TTLSeconds: 10, MaxSharedLocks: 10, Weight: 5})
+	require.NoError(t, err)
+	require.True(t, result1.Acquired)
+
+	result2, err := client.TrySLock(ctx, TrySLockParams{Name: "test_memory_shared", LockID: "medium", TTLSeconds: 20, MaxSharedLocks: 10, Weight: 4})
+	require.NoError(t, err)
+	require.True(t, result2.Acquired)
+
+	// 2. 남은 permit보다 큰 요청은 실패
+	result3, err := client.TrySLock(ctx, TrySLockParams{Name: "test_memory_shared", LockID: "small", TTLSeconds: 10, MaxSharedLocks: 10, Weight: 2})
+	require.NoError(t, err)
+	assert.False(t, result3.Acquired)
+
+	// 3. SLock이 있는 동안 XLock은 실패
+	result4, err := client.TryXLock(ctx, TryXLockParams{Name: "test_memory_shared", LockID: "writer", TTLSeconds: 10})
+	require.NoError(t, err)
+	assert.False(t, result4.Acquired)
+
+	// 4. big이 만료되면 permit이 반환됨
+	clock.Advance(10 * time.Second)
+	result5, err := client.TrySLock(ctx, TrySLockParams{Name: "test_memory_shared", LockID: "small", TTLSeconds: 10, MaxSharedLocks: 10, Weight: 2})
+	require.NoError(t, err)
+	assert.True(t, result5.Acquired)
+
+	describeResult, err := client.DescribeLock(ctx, DescribeLockParams{Name: "test_memory_shared"})
+	require.NoError(t, err)
+	require.True(t, describeResult.Found)
+	assert.Len(t, describeResult.Lock.SharedLocks, 2)
+	assert.Equal(t, 6, totalSharedWeight(describeResult.Lock.SharedLocks))
+}
+
+// TestMemoryLockClient_XLockWaitsForUnlock tests that a blocked XLock is woken up by Unlock and that holders never overlap
+func TestMemoryLockClient_XLockWaitsForUnlock(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryLockClient(MemoryLockClientOptions{})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	holding := 0
+	maxHolding := 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			lockID := fmt.Sprintf("xlock_%d", id)
+			_, err := client.XLock(ctx, XLockParams{
+				Name:             "test_memory_xlock",
+				LockID:           lockID,
+				TTLSeconds:       60,
+				IntervalDuration: time.Minute, // 재시도 간격이 아니라 Unlock 알림으로 깨어나야 함
+			})
+			require.NoError(t, err)
+
+			mu.Lock()
+			holding++
+			maxHolding = max(maxHolding, holding)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			holding--
+			mu.Unlock()
+
+			_, err = client.Unlock(ctx, UnlockParams{Name: "test_memory_xlock", LockID: lockID})
+			require.NoError(t, err)
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("XLock waiters were not woken up by Unlock")
+	}
+
+	assert.Equal(t, 1, maxHolding)
+}
+
+// testBackend is a memory client on a manual clock, used by the feature tests.
+type testBackend struct {
+	Client LockClient
+	clock  *ManualClock
+}
+
+func newTestBackend(t *testing.T) testBackend {
+	clock := NewManualClock(time.Now())
+
+	return testBackend{
+		Client: NewMemoryLockClient(MemoryLockClientOptions{Clock: clock}),
+		clock:  clock,
+	}
+}
+
+// expire advances the clock past the given TTL.
+func (b testBackend) expire(ttlSeconds int) {
+	b.clock.Advance(time.Duration(ttlSeconds)*time.Second + 100*time.Millisecond)
+}
+
+// testLockName returns a lock name unique to the test.
+func testLockName(t *testing.T) string {
+	return fmt.Sprintf("test/%s/%d", t.Name(), time.Now().UnixNano())
+}
+
+func tryXLock(t *testing.T, client LockClient, name string, lockID string, ttlSeconds int) bool {
+	t.Helper()
+
+	result, err := client.TryXLock(context.Background(), TryXLockParams{
+		Name:       name,
+		LockID:     lockID,
+		TTLSeconds: ttlSeconds,
+	})
+	require.NoError(t, err)
+
+	return result.Acquired
+}
+
+func trySLock(t *testing.T, client LockClient, name string, lockID string, maxSharedLocks int, weight int) bool {
+	t.Helper()
+
+	result, err := client.TrySLock(context.Background(), TrySLockParams{
+		Name:           name,
+		LockID:         lockID,
+		TTLSeconds:     60,
+		MaxSharedLocks: maxSharedLocks,
+		Weight:         weight,
+	})
+	require.NoError(t, err)
+
+	return result.Acquired
+}
+
+func unlock(t *testing.T, client LockClient, name string, lockID string) bool {
+	t.Helper()
+
+	result, err := client.Unlock(context.Background(), UnlockParams{
+		Name:   name,
+		LockID: lockID,
+	})
+	require.NoError(t, err)
+
+	return result.Released
+}
@@ -0,0 +1,125 @@
+// Package prometheus provides a pglock.Observer that exports lock operation metrics to Prometheus.
+package prometheus
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/myyrakle/pglock"
+)
+
+type Options struct {
+	Namespace       string          // [optional] metric namespace. default: "pglock"
+	IncludeLockName bool            // [optional] add a "name" label with the lock name (beware of label cardinality). default: false
+	DurationBuckets []float64       // [optional] buckets of the duration histogram in seconds. default: prometheus.DefBuckets
+	AttemptsBuckets []float64       // [optional] buckets of the attempts histogram. default: 1, 2, 5, 10, 20, 50, 100, 200, 500
+	Registerer      prom.Registerer // [optional] registerer the collectors are registered to. default: not registered
+}
+
+func (options *Options) SetDefaults() {
+	if options.Namespace == "" {
+		options.Namespace = "pglock"
+	}
+	if options.DurationBuckets == nil {
+		options.DurationBuckets = prom.DefBuckets
+	}
+	if options.AttemptsBuckets == nil {
+		options.AttemptsBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500}
+	}
+}
+
+// Observer records pglock.LockEvent values as Prometheus metrics:
+//
+//   - <namespace>_operations_total: counter of operations by operation, mode and outcome
+//   - <namespace>_operation_duration_seconds: histogram of operation latency, including wait time
+//   - <namespace>_acquire_attempts: histogram of attempts made by XLock and SLock
+//
+// Contention shows up as the "not_acquired" outcome and lease losses as the "lost" outcome of "refresh".
+type Observer struct {
+	options Options
+
+	operations *prom.CounterVec
+	durations  *prom.HistogramVec
+	attempts   *prom.HistogramVec
+}
+
+var _ pglock.Observer = (*Observer)(nil)
+var _ prom.Collector = (*Observer)(nil)
+
+// NewObserver creates the collectors and registers them to options.Registerer if it is set.
+func NewObserver(options Options) (*Observer, error) {
+	options.SetDefaults()
+
+	labels := []string{"operation", "mode", "outcome"}
+	attemptsLabels := []string{"operation", "mode"}
+	if options.IncludeLockName {
+		labels = append(labels, "name")
+		attemptsLabels = append(attemptsLabels, "name")
+	}
+
+	observer := &Observer{
+		options: options,
+		operations: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: options.Namespace,
+			Name:      "operations_total",
+			Help:      "Number of lock operations by operation, mode and outcome.",
+		}, labels),
+		durations: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: options.Namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of lock operations in seconds, including time spent waiting for the lock.",
+			Buckets:   options.DurationBuckets,
+		}, labels),
+		attempts: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: options.Namespace,
+			Name:      "acquire_attempts",
+			Help:      "Number of attempts made by blocking acquire operations.",
+			Buckets:   options.AttemptsBuckets,
+		}, attemptsLabels),
+	}
+
+	if options.Registerer != nil {
+		if err := options.Registerer.Register(observer); err != nil {
+			return nil, err
+		}
+	}
+
+	return observer, nil
+}
+
+// ObserveLockOperation implements pglock.Observer.
+func (o *Observer) ObserveLockOperation(event pglock.LockEvent) {
+	labels := prom.Labels{
+		"operation": string(event.Operation),
+		"mode":      string(event.Mode),
+		"outcome":   string(event.Outcome),
+	}
+	attemptsLabels := prom.Labels{
+		"operation": string(event.Operation),
+		"mode":      string(event.Mode),
+	}
+	if o.options.IncludeLockName {
+		labels["name"] = event.Name
+		attemptsLabels["name"] = event.Name
+	}
+
+	o.operations.With(labels).Inc()
+	o.durations.With(labels).Observe(event.Duration.Seconds())
+
+	if event.Operation == pglock.OperationXLock || event.Operation == pglock.OperationSLock {
+		o.attempts.With(attemptsLabels).Observe(float64(event.Attempts))
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (o *Observer) Describe(ch chan<- *prom.Desc) {
+	o.operations.Describe(ch)
+	o.durations.Describe(ch)
+	o.attempts.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (o *Observer) Collect(ch chan<- prom.Metric) {
+	o.operations.Collect(ch)
+	o.durations.Collect(ch)
+	o.attempts.Collect(ch)
+}
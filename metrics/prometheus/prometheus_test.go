@@ -0,0 +1,49 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/myyrakle/pglock"
+)
+
+// TestObserver_RecordsEvents tests that lock events are exported as metrics
+func TestObserver_RecordsEvents(t *testing.T) {
+	registry := prom.NewRegistry()
+
+	observer, err := NewObserver(Options{Registerer: registry})
+	require.NoError(t, err)
+
+	observer.ObserveLockOperation(pglock.LockEvent{
+		Operation: pglock.OperationXLock,
+		Name:      "test_lock",
+		Mode:      pglock.LockModeExclusive,
+		Outcome:   pglock.OutcomeAcquired,
+		Attempts:  3,
+		Duration:  250 * time.Millisecond,
+	})
+	observer.ObserveLockOperation(pglock.LockEvent{
+		Operation: pglock.OperationRefresh,
+		Name:      "test_lock",
+		Outcome:   pglock.OutcomeLost,
+		Attempts:  1,
+		Duration:  time.Millisecond,
+	})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(observer.operations.With(prom.Labels{
+		"operation": "xlock", "mode": "exclusive", "outcome": "acquired",
+	})))
+	assert.Equal(t, 1.0, testutil.ToFloat64(observer.operations.With(prom.Labels{
+		"operation": "refresh", "mode": "", "outcome": "lost",
+	})))
+
+	// refresh는 시도 횟수 히스토그램에 기록되지 않음
+	count, err := testutil.GatherAndCount(registry, "pglock_acquire_attempts")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
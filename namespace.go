@@ -0,0 +1,449 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NamespaceSeparator separates the namespace from the lock name in the stored name ("<namespace>:<name>")
+const NamespaceSeparator = ":"
+
+// NamespacedName returns the name stored in the lock table for a lock name in a namespace.
+func NamespacedName(namespace string, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + NamespaceSeparator + name
+}
+
+// SplitNamespace splits a stored lock name into its namespace and lock name.
+// Names without a separator belong to the empty namespace. A name taken without a namespace that
+// contains the separator (e.g. "orders:42" from before namespaces were used) cannot be told apart from
+// a namespaced one, and is split as the lock "42" of the namespace "orders".
+func SplitNamespace(storedName string) (namespace string, name string) {
+	namespace, name, found := strings.Cut(storedName, NamespaceSeparator)
+	if !found {
+		return "", storedName
+	}
+
+	return namespace, name
+}
+
+// WithNamespace returns a LockClient that prefixes every lock name (and policy name) with namespace,
+// and strips the prefix from the names it returns, so that services sharing a lock table cannot collide.
+// Sessions and ListNamespaces are not namespaced.
+// It panics if namespace contains NamespaceSeparator, which would make stored names ambiguous.
+func WithNamespace(client LockClient, namespace string) LockClient {
+	if namespace == "" {
+		return client
+	}
+	if err := validateNamespace(namespace); err != nil {
+		panic(err)
+	}
+
+	return &namespacedLockClient{LockClient: client, namespace: namespace}
+}
+
+// errInvalidNamespace is returned when a namespace contains NamespaceSeparator
+var errInvalidNamespace = errors.New("pglock: namespace must not contain " + strconv.Quote(NamespaceSeparator))
+
+// validateNamespace rejects namespaces that would make stored names ambiguous.
+func validateNamespace(namespace string) error {
+	if strings.Contains(namespace, NamespaceSeparator) {
+		return errInvalidNamespace
+	}
+
+	return nil
+}
+
+type namespacedLockClient struct {
+	LockClient
+	namespace string
+}
+
+// The store methods are forwarded when the wrapped client implements them, and fail with ErrNotSupported otherwise.
+var (
+	_ BarrierStore    = (*namespacedLockClient)(nil)
+	_ LatchStore      = (*namespacedLockClient)(nil)
+	_ TaskResultStore = (*namespacedLockClient)(nil)
+	_ JobStatusStore  = (*namespacedLockClient)(nil)
+)
+
+func (c *namespacedLockClient) name(name string) string {
+	return NamespacedName(c.namespace, name)
+}
+
+// strip returns the name as seen inside the namespace. Names from other namespaces
+// (e.g. a prefix policy covering several namespaces) are returned as stored.
+func (c *namespacedLockClient) strip(storedName string) string {
+	name, found := strings.CutPrefix(storedName, c.namespace+NamespaceSeparator)
+	if !found {
+		return storedName
+	}
+
+	return name
+}
+
+func (c *namespacedLockClient) TryXLock(ctx context.Context, params TryXLockParams) (TryXLockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.TryXLock(ctx, params)
+}
+
+func (c *namespacedLockClient) XLock(ctx context.Context, params XLockParams) (XLockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.XLock(ctx, params)
+}
+
+func (c *namespacedLockClient) TrySLock(ctx context.Context, params TrySLockParams) (TrySLockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.TrySLock(ctx, params)
+}
+
+func (c *namespacedLockClient) SLock(ctx context.Context, params SLockParams) (SLockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.SLock(ctx, params)
+}
+
+func (c *namespacedLockClient) TryXLockTx(ctx context.Context, tx *sql.Tx, params TryXLockTxParams) (TryXLockTxResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.TryXLockTx(ctx, tx, params)
+}
+
+func (c *namespacedLockClient) XLockTx(ctx context.Context, tx *sql.Tx, params XLockTxParams) (XLockTxResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.XLockTx(ctx, tx, params)
+}
+
+func (c *namespacedLockClient) TrySLockTx(ctx context.Context, tx *sql.Tx, params TrySLockTxParams) (TrySLockTxResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.TrySLockTx(ctx, tx, params)
+}
+
+func (c *namespacedLockClient) TryHierarchyLock(ctx context.Context, params TryHierarchyLockParams) (TryHierarchyLockResult, error) {
+	params.Name = c.name(params.Name)
+
+	result, err := c.LockClient.TryHierarchyLock(ctx, params)
+	result.Conflict.Name = c.strip(result.Conflict.Name)
+	result.Conflict.Target = c.strip(result.Conflict.Target)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) HierarchyLock(ctx context.Context, params HierarchyLockParams) (HierarchyLockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.HierarchyLock(ctx, params)
+}
+
+func (c *namespacedLockClient) HierarchyUnlock(ctx context.Context, params HierarchyUnlockParams) (HierarchyUnlockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.HierarchyUnlock(ctx, params)
+}
+
+func (c *namespacedLockClient) ArriveBarrier(ctx context.Context, params ArriveBarrierParams) (ArriveBarrierResult, error) {
+	store, err := storeOf[BarrierStore](c.LockClient)
+	if err != nil {
+		return ArriveBarrierResult{}, err
+	}
+
+	params.Name = c.name(params.Name)
+	return store.ArriveBarrier(ctx, params)
+}
+
+func (c *namespacedLockClient) LeaveBarrier(ctx context.Context, params LeaveBarrierParams) (LeaveBarrierResult, error) {
+	store, err := storeOf[BarrierStore](c.LockClient)
+	if err != nil {
+		return LeaveBarrierResult{}, err
+	}
+
+	params.Name = c.name(params.Name)
+	return store.LeaveBarrier(ctx, params)
+}
+
+func (c *namespacedLockClient) CreateLatch(ctx context.Context, params CreateLatchParams) (CreateLatchResult, error) {
+	store, err := storeOf[LatchStore](c.LockClient)
+	if err != nil {
+		return CreateLatchResult{}, err
+	}
+
+	params.Name = c.name(params.Name)
+
+	result, err := store.CreateLatch(ctx, params)
+	result.Latch.Name = c.strip(result.Latch.Name)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) CountDown(ctx context.Context, params CountDownParams) (CountDownResult, error) {
+	store, err := storeOf[LatchStore](c.LockClient)
+	if err != nil {
+		return CountDownResult{}, err
+	}
+
+	params.Name = c.name(params.Name)
+	return store.CountDown(ctx, params)
+}
+
+func (c *namespacedLockClient) DescribeLatch(ctx context.Context, params DescribeLatchParams) (DescribeLatchResult, error) {
+	store, err := storeOf[LatchStore](c.LockClient)
+	if err != nil {
+		return DescribeLatchResult{}, err
+	}
+
+	params.Name = c.name(params.Name)
+
+	result, err := store.DescribeLatch(ctx, params)
+	result.Latch.Name = c.strip(result.Latch.Name)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) StoreTaskResult(ctx context.Context, params StoreTaskResultParams) (StoreTaskResultResult, error) {
+	store, err := storeOf[TaskResultStore](c.LockClient)
+	if err != nil {
+		return StoreTaskResultResult{}, err
+	}
+
+	params.Key = c.name(params.Key)
+
+	result, err := store.StoreTaskResult(ctx, params)
+	result.Result.Key = c.strip(result.Result.Key)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) GetTaskResult(ctx context.Context, params GetTaskResultParams) (GetTaskResultResult, error) {
+	store, err := storeOf[TaskResultStore](c.LockClient)
+	if err != nil {
+		return GetTaskResultResult{}, err
+	}
+
+	params.Key = c.name(params.Key)
+
+	result, err := store.GetTaskResult(ctx, params)
+	result.Result.Key = c.strip(result.Result.Key)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) DeleteTaskResult(ctx context.Context, params DeleteTaskResultParams) (DeleteTaskResultResult, error) {
+	store, err := storeOf[TaskResultStore](c.LockClient)
+	if err != nil {
+		return DeleteTaskResultResult{}, err
+	}
+
+	params.Key = c.name(params.Key)
+	return store.DeleteTaskResult(ctx, params)
+}
+
+func (c *namespacedLockClient) PruneTaskResults(ctx context.Context, params PruneTaskResultsParams) (PruneTaskResultsResult, error) {
+	store, err := storeOf[TaskResultStore](c.LockClient)
+	if err != nil {
+		return PruneTaskResultsResult{}, err
+	}
+
+	// 만료된 결과만 지우므로 네임스페이스와 무관하게 테이블 전체 대상
+	return store.PruneTaskResults(ctx, params)
+}
+
+func (c *namespacedLockClient) SaveJobStatus(ctx context.Context, params SaveJobStatusParams) (SaveJobStatusResult, error) {
+	store, err := storeOf[JobStatusStore](c.LockClient)
+	if err != nil {
+		return SaveJobStatusResult{}, err
+	}
+
+	params.Status.Name = c.name(params.Status.Name)
+	return store.SaveJobStatus(ctx, params)
+}
+
+func (c *namespacedLockClient) GetJobStatus(ctx context.Context, params GetJobStatusParams) (GetJobStatusResult, error) {
+	store, err := storeOf[JobStatusStore](c.LockClient)
+	if err != nil {
+		return GetJobStatusResult{}, err
+	}
+
+	params.Name = c.name(params.Name)
+
+	result, err := store.GetJobStatus(ctx, params)
+	result.Status.Name = c.strip(result.Status.Name)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) GetValue(ctx context.Context, params GetValueParams) (GetValueResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.GetValue(ctx, params)
+}
+
+func (c *namespacedLockClient) SetValue(ctx context.Context, params SetValueParams) (SetValueResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.SetValue(ctx, params)
+}
+
+func (c *namespacedLockClient) Unlock(ctx context.Context, params UnlockParams) (UnlockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.Unlock(ctx, params)
+}
+
+func (c *namespacedLockClient) Refresh(ctx context.Context, params RefreshParams) (RefreshResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.Refresh(ctx, params)
+}
+
+func (c *namespacedLockClient) ForceUnlock(ctx context.Context, params ForceUnlockParams) (ForceUnlockResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.ForceUnlock(ctx, params)
+}
+
+func (c *namespacedLockClient) DescribeLock(ctx context.Context, params DescribeLockParams) (DescribeLockResult, error) {
+	params.Name = c.name(params.Name)
+
+	result, err := c.LockClient.DescribeLock(ctx, params)
+	result.Lock.Name = c.strip(result.Lock.Name)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) ListLocks(ctx context.Context, params ListLocksParams) (ListLocksResult, error) {
+	params.Prefix = c.name(params.Prefix)
+
+	result, err := c.LockClient.ListLocks(ctx, params)
+	for i := range result.Locks {
+		result.Locks[i].Name = c.strip(result.Locks[i].Name)
+	}
+
+	return result, err
+}
+
+func (c *namespacedLockClient) SetLockPolicy(ctx context.Context, params SetLockPolicyParams) (SetLockPolicyResult, error) {
+	params.Name = c.name(params.Name)
+
+	result, err := c.LockClient.SetLockPolicy(ctx, params)
+	result.Policy.Name = c.strip(result.Policy.Name)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) GetLockPolicy(ctx context.Context, params GetLockPolicyParams) (GetLockPolicyResult, error) {
+	params.Name = c.name(params.Name)
+
+	result, err := c.LockClient.GetLockPolicy(ctx, params)
+	result.Policy.Name = c.strip(result.Policy.Name)
+
+	return result, err
+}
+
+func (c *namespacedLockClient) DeleteLockPolicy(ctx context.Context, params DeleteLockPolicyParams) (DeleteLockPolicyResult, error) {
+	params.Name = c.name(params.Name)
+	return c.LockClient.DeleteLockPolicy(ctx, params)
+}
+
+func (c *namespacedLockClient) GetLockHistory(ctx context.Context, params GetLockHistoryParams) (GetLockHistoryResult, error) {
+	params.Name = c.name(params.Name)
+
+	result, err := c.LockClient.GetLockHistory(ctx, params)
+	for i := range result.Events {
+		result.Events[i].Name = c.strip(result.Events[i].Name)
+	}
+
+	return result, err
+}
+
+// NamespaceInfo summarizes the locks of a namespace.
+type NamespaceInfo struct {
+	Namespace string // Namespace ("" for lock names without a namespace)
+	Locks     int    // Number of locks in the namespace
+	HeldLocks int    // Number of locks with at least one live holder
+}
+
+type ListNamespacesParams struct{}
+
+type ListNamespacesResult struct {
+	Namespaces []NamespaceInfo // Namespaces ordered by name
+}
+
+// countNamespaces adds a lock to the summary of its namespace.
+func countNamespaces(counts map[string]*NamespaceInfo, lock LockInfo) {
+	namespace, _ := SplitNamespace(lock.Name)
+
+	info, ok := counts[namespace]
+	if !ok {
+		info = &NamespaceInfo{Namespace: namespace}
+		counts[namespace] = info
+	}
+
+	info.Locks++
+	if lock.XLockID != "" || len(lock.SharedLocks) > 0 {
+		info.HeldLocks++
+	}
+}
+
+// sortedNamespaces returns the namespace summaries ordered by name.
+func sortedNamespaces(counts map[string]*NamespaceInfo) []NamespaceInfo {
+	namespaces := make([]NamespaceInfo, 0, len(counts))
+	for _, info := range counts {
+		namespaces = append(namespaces, *info)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Namespace < namespaces[j].Namespace
+	})
+
+	return namespaces
+}
+
+// ListNamespaces returns the namespaces in the lock table with their lock counts.
+func (c *lockClient) ListNamespaces(ctx context.Context, params ListNamespacesParams) (ListNamespacesResult, error) {
+	// 보유 여부(만료와 세션 종료 포함)까지 한 번의 쿼리로 집계
+	selectQuery := fmt.Sprintf(`
+		SELECT namespace, COUNT(*), COUNT(*) FILTER (WHERE held)
+		FROM (
+			SELECT
+				CASE WHEN strpos(l.name, $2) > 0 THEN split_part(l.name, $2, 1) ELSE '' END AS namespace,
+				(
+					(
+						l.xlock_id IS NOT NULL AND l.x_expires_at > $1
+						AND (l.x_session_id IS NULL OR EXISTS (
+							SELECT 1 FROM %[2]s s WHERE s.id = l.x_session_id AND s.expires_at > $1
+						))
+					)
+					OR EXISTS (
+						SELECT 1
+						FROM jsonb_array_elements(l.shared_locks) AS entry
+						WHERE (entry->>'expires_at')::timestamptz > $1
+							AND (COALESCE(entry->>'session_id', '') = '' OR EXISTS (
+								SELECT 1 FROM %[2]s s WHERE s.id = entry->>'session_id' AND s.expires_at > $1
+							))
+					)
+				) AS held
+			FROM %[1]s l
+		) AS locks
+		GROUP BY namespace;
+	`, c.options.LockTableName, c.options.LockSessionTableName)
+
+	rows, err := c.db.QueryContext(ctx, selectQuery, time.Now(), NamespaceSeparator)
+	if err != nil {
+		return ListNamespacesResult{}, err
+	}
+	defer rows.Close()
+
+	counts := map[string]*NamespaceInfo{}
+	for rows.Next() {
+		info := &NamespaceInfo{}
+		if err := rows.Scan(&info.Namespace, &info.Locks, &info.HeldLocks); err != nil {
+			return ListNamespacesResult{}, err
+		}
+
+		counts[info.Namespace] = info
+	}
+	if err := rows.Err(); err != nil {
+		return ListNamespacesResult{}, err
+	}
+
+	return ListNamespacesResult{Namespaces: sortedNamespaces(counts)}, nil
+}
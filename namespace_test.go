@@ -0,0 +1,80 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithNamespace_IsolatesNames tests that clients in different namespaces do not collide on the same lock name
+func TestWithNamespace_IsolatesNames(t *testing.T) {
+	ctx := context.Background()
+	shared := NewMemoryLockClient(MemoryLockClientOptions{})
+	billing := WithNamespace(shared, "billing")
+	search := WithNamespace(shared, "search")
+
+	// 1. 같은 이름이라도 namespace가 다르면 각각 획득 가능
+	result1, err := billing.TryXLock(ctx, TryXLockParams{Name: "sync", LockID: "billing_worker", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.True(t, result1.Acquired)
+
+	result2, err := search.TryXLock(ctx, TryXLockParams{Name: "sync", LockID: "search_worker", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.True(t, result2.Acquired)
+
+	result3, err := billing.TryXLock(ctx, TryXLockParams{Name: "sync", LockID: "other", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, result3.Acquired)
+
+	// 2. 조회 결과의 이름에는 namespace가 붙지 않음
+	describeResult, err := billing.DescribeLock(ctx, DescribeLockParams{Name: "sync"})
+	require.NoError(t, err)
+	assert.Equal(t, "sync", describeResult.Lock.Name)
+	assert.Equal(t, "billing_worker", describeResult.Lock.XLockID)
+
+	listResult, err := search.ListLocks(ctx, ListLocksParams{})
+	require.NoError(t, err)
+	require.Len(t, listResult.Locks, 1)
+	assert.Equal(t, "sync", listResult.Locks[0].Name)
+	assert.Equal(t, "search_worker", listResult.Locks[0].XLockID)
+
+	// 3. 저장된 이름은 "<namespace>:<name>"
+	rawResult, err := shared.DescribeLock(ctx, DescribeLockParams{Name: "billing:sync"})
+	require.NoError(t, err)
+	assert.True(t, rawResult.Found)
+
+	// 4. namespace별 락 수 집계
+	_, err = shared.TryXLock(ctx, TryXLockParams{Name: "legacy", LockID: "old_worker", TTLSeconds: 60})
+	require.NoError(t, err)
+	_, err = search.Unlock(ctx, UnlockParams{Name: "sync", LockID: "search_worker"})
+	require.NoError(t, err)
+
+	namespaces, err := billing.ListNamespaces(ctx, ListNamespacesParams{})
+	require.NoError(t, err)
+	assert.Equal(t, []NamespaceInfo{
+		{Namespace: "", Locks: 1, HeldLocks: 1},
+		{Namespace: "billing", Locks: 1, HeldLocks: 1},
+		{Namespace: "search", Locks: 1, HeldLocks: 0},
+	}, namespaces.Namespaces)
+}
+
+// TestWithNamespace_RejectsSeparator tests that a namespace containing the separator is refused
+func TestWithNamespace_RejectsSeparator(t *testing.T) {
+	client := NewMemoryLockClient(MemoryLockClientOptions{})
+
+	assert.Panics(t, func() { WithNamespace(client, "billing:eu") })
+	assert.NotPanics(t, func() { WithNamespace(client, "billing-eu") })
+}
+
+// TestNewLockClient_RejectsNamespaceSeparator tests that a configured namespace containing the separator fails Connect instead of panicking
+func TestNewLockClient_RejectsNamespaceSeparator(t *testing.T) {
+	var client LockClient
+	require.NotPanics(t, func() {
+		client = NewLockClient(LockClientOptions{DatabaseURL: "postgres://localhost/unused", Namespace: "billing:eu"})
+	})
+
+	assert.ErrorIs(t, client.Connect(), errInvalidNamespace)
+	assert.ErrorIs(t, client.Initialize(), errInvalidNamespace)
+}
@@ -0,0 +1,84 @@
+package pglock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LockMode is the mode of a lock operation
+type LockMode string
+
+const (
+	LockModeExclusive LockMode = "exclusive"
+	LockModeShared    LockMode = "shared"
+)
+
+// LockOperation identifies the LockClient method that produced a LockEvent
+type LockOperation string
+
+const (
+	OperationTryXLock LockOperation = "try_xlock"
+	OperationXLock    LockOperation = "xlock"
+	OperationTrySLock LockOperation = "try_slock"
+	OperationSLock    LockOperation = "slock"
+	OperationUnlock   LockOperation = "unlock"
+	OperationRefresh  LockOperation = "refresh"
+
+	OperationTryXLockTx LockOperation = "try_xlock_tx"
+	OperationXLockTx    LockOperation = "xlock_tx"
+	OperationTrySLockTx LockOperation = "try_slock_tx"
+)
+
+// LockOutcome is the result of a lock operation
+type LockOutcome string
+
+const (
+	OutcomeAcquired    LockOutcome = "acquired"     // The lock was acquired
+	OutcomeNotAcquired LockOutcome = "not_acquired" // The lock is held by someone else (contention)
+	OutcomeReleased    LockOutcome = "released"     // The lock was released
+	OutcomeNotHeld     LockOutcome = "not_held"     // Unlock found nothing to release
+	OutcomeRefreshed   LockOutcome = "refreshed"    // The lock was extended
+	OutcomeLost        LockOutcome = "lost"         // Refresh found the lock expired or taken over (lease loss)
+	OutcomeCanceled    LockOutcome = "canceled"     // The context was canceled or timed out while waiting
+	OutcomeError       LockOutcome = "error"        // The operation failed with an error
+)
+
+// LockEvent describes a single completed lock operation
+type LockEvent struct {
+	Operation LockOperation // Which operation was performed
+	Name      string        // Lock Name
+	Mode      LockMode      // Lock mode ("" for Unlock and Refresh, which apply to either mode)
+	Outcome   LockOutcome   // Result of the operation
+	Attempts  int           // Number of acquisition attempts (1 for non-blocking operations)
+	Duration  time.Duration // Total time spent in the operation, including waiting
+	Err       error         // Error returned to the caller, if any
+}
+
+// Observer receives a LockEvent for every TryXLock, XLock, TrySLock, SLock, Unlock and Refresh call.
+// ObserveLockOperation is called synchronously and concurrently, so it must be fast and safe for concurrent use.
+type Observer interface {
+	ObserveLockOperation(event LockEvent)
+}
+
+func (i *instrumentation) observe(event LockEvent) {
+	if i.observer == nil {
+		return
+	}
+
+	i.observer.ObserveLockOperation(event)
+}
+
+// outcomeOf maps the result of an operation to a LockOutcome.
+func outcomeOf(err error, succeeded bool, success LockOutcome, failure LockOutcome) LockOutcome {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return OutcomeCanceled
+	case err != nil:
+		return OutcomeError
+	case succeeded:
+		return success
+	default:
+		return failure
+	}
+}
@@ -0,0 +1,72 @@
+package pglock
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// OwnerInfo describes the process holding a lock, so that DescribeLock and ListLocks can tell
+// which host and process to look at when a lock is stuck.
+type OwnerInfo struct {
+	Hostname   string          `json:"hostname"`           // Host of the holder process
+	PID        int             `json:"pid"`                // Process ID of the holder
+	AcquiredAt time.Time       `json:"acquired_at"`        // Time the lock was acquired (kept when a shared lock is renewed)
+	Metadata   json.RawMessage `json:"metadata,omitempty"` // Metadata passed with the acquisition (e.g. version, request ID)
+}
+
+var processHostname = sync.OnceValue(func() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+
+	return hostname
+})
+
+// LocalOwner fills the empty Hostname and PID of owner with those of the current process.
+// Clients that forward requests to another process (e.g. the remote client) call it before sending.
+func LocalOwner(owner OwnerInfo) OwnerInfo {
+	if owner.Hostname == "" {
+		owner.Hostname = processHostname()
+	}
+	if owner.PID == 0 {
+		owner.PID = os.Getpid()
+	}
+
+	return owner
+}
+
+// newOwner returns the owner recorded for an acquisition at now. Fields left empty in owner are
+// filled with the current process, the acquisition time and the metadata of the request.
+func newOwner(owner OwnerInfo, metadata json.RawMessage, now time.Time) *OwnerInfo {
+	owner = LocalOwner(owner)
+	if owner.AcquiredAt.IsZero() {
+		owner.AcquiredAt = now
+	}
+	if len(owner.Metadata) == 0 {
+		owner.Metadata = metadata
+	}
+
+	return &owner
+}
+
+// nullOwner stores an owner as JSONB, or NULL if there is none.
+func nullOwner(owner *OwnerInfo) ([]byte, error) {
+	if owner == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(owner)
+}
+
+// renewOwner returns the owner recorded when a shared lock is renewed: the new details replace the
+// previous ones, but the original acquisition time is kept unless the caller set one explicitly.
+func renewOwner(previous *OwnerInfo, renewed *OwnerInfo, requested OwnerInfo) *OwnerInfo {
+	if previous != nil && requested.AcquiredAt.IsZero() {
+		renewed.AcquiredAt = previous.AcquiredAt
+	}
+
+	return renewed
+}
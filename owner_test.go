@@ -0,0 +1,85 @@
+package pglock
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOwner_RecordedForHolders tests that the owner of each holder is recorded and returned by DescribeLock and ListLocks
+func TestOwner_RecordedForHolders(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	prefix := testLockName(t) + "/"
+	xName := prefix + "x"
+	sName := prefix + "s"
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	// 1. 비어 있는 필드는 현재 프로세스와 획득 요청의 metadata로 채워짐
+	xResult, err := client.TryXLock(ctx, TryXLockParams{
+		Name: xName, LockID: "writer", TTLSeconds: 60, Metadata: json.RawMessage(`{"version":"1.2.0"}`),
+	})
+	require.NoError(t, err)
+	require.True(t, xResult.Acquired)
+
+	describeResult, err := client.DescribeLock(ctx, DescribeLockParams{Name: xName})
+	require.NoError(t, err)
+	owner := describeResult.Lock.XOwner
+	require.NotNil(t, owner)
+	assert.Equal(t, hostname, owner.Hostname)
+	assert.Equal(t, os.Getpid(), owner.PID)
+	assert.False(t, owner.AcquiredAt.IsZero())
+	assert.JSONEq(t, `{"version":"1.2.0"}`, string(owner.Metadata))
+
+	// 2. 지정한 필드는 그대로 기록되고, 공유 락 갱신 시 획득 시각은 유지됨
+	sResult, err := client.TrySLock(ctx, TrySLockParams{
+		Name: sName, LockID: "reader", TTLSeconds: 60, MaxSharedLocks: -1,
+		Owner: OwnerInfo{Hostname: "worker-7", Metadata: json.RawMessage(`{"request_id":"r1"}`)},
+	})
+	require.NoError(t, err)
+	require.True(t, sResult.Acquired)
+
+	describeResult, err = client.DescribeLock(ctx, DescribeLockParams{Name: sName})
+	require.NoError(t, err)
+	require.Len(t, describeResult.Lock.SharedLocks, 1)
+	sharedOwner := describeResult.Lock.SharedLocks[0].Owner
+	require.NotNil(t, sharedOwner)
+	assert.Equal(t, "worker-7", sharedOwner.Hostname)
+	assert.Equal(t, os.Getpid(), sharedOwner.PID)
+	acquiredAt := sharedOwner.AcquiredAt
+
+	backend.expire(0) // 획득 시각이 달라지도록 시간을 약간 진행
+	sResult, err = client.TrySLock(ctx, TrySLockParams{
+		Name: sName, LockID: "reader", TTLSeconds: 60, MaxSharedLocks: -1,
+		Owner: OwnerInfo{Hostname: "worker-7", Metadata: json.RawMessage(`{"request_id":"r2"}`)},
+	})
+	require.NoError(t, err)
+	require.True(t, sResult.Acquired)
+
+	// 3. ListLocks도 같은 보유자 정보를 반환
+	listResult, err := client.ListLocks(ctx, ListLocksParams{Prefix: prefix})
+	require.NoError(t, err)
+	require.Len(t, listResult.Locks, 2)
+	assert.Equal(t, owner.AcquiredAt.UnixNano(), listResult.Locks[1].XOwner.AcquiredAt.UnixNano())
+	require.Len(t, listResult.Locks[0].SharedLocks, 1)
+	sharedOwner = listResult.Locks[0].SharedLocks[0].Owner
+	require.NotNil(t, sharedOwner)
+	assert.True(t, acquiredAt.Equal(sharedOwner.AcquiredAt))
+	assert.JSONEq(t, `{"request_id":"r2"}`, string(sharedOwner.Metadata))
+
+	// 4. 해제되면 보유자 정보도 사라짐
+	unlockResult, err := client.Unlock(ctx, UnlockParams{Name: xName, LockID: "writer"})
+	require.NoError(t, err)
+	require.True(t, unlockResult.Released)
+
+	describeResult, err = client.DescribeLock(ctx, DescribeLockParams{Name: xName})
+	require.NoError(t, err)
+	assert.Nil(t, describeResult.Lock.XOwner)
+}
@@ -0,0 +1,666 @@
+// Package pglocktest provides test utilities for pglock.LockClient implementations.
+package pglocktest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/myyrakle/pglock"
+)
+
+// Backend is a LockClient under test.
+type Backend struct {
+	Client pglock.LockClient // [required] an initialized client
+
+	// [optional] moves the clock the client uses for expiry forward by d.
+	// If nil, the suite waits in real time for locks to expire.
+	Advance func(d time.Duration)
+
+	// [optional] the clock the client uses for expiry, used to timestamp recorded histories.
+	// default: the system clock
+	Clock pglock.Clock
+}
+
+// Factory creates the backend for a single conformance test.
+// It may return the same client for every test; each test uses its own lock names.
+type Factory func(t *testing.T) Backend
+
+// RunConformance verifies a LockClient against the contract of the Postgres implementation:
+// exclusive/shared conflicts, capacity limits, expiry, refresh, unlock ownership and concurrency invariants.
+func RunConformance(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, backend Backend)
+	}{
+		{"XLockExcludesXLock", testXLockExcludesXLock},
+		{"XLockExcludesSLock", testXLockExcludesSLock},
+		{"SLockExcludesXLock", testSLockExcludesXLock},
+		{"SLockCapacity", testSLockCapacity},
+		{"SLockWeightedCapacity", testSLockWeightedCapacity},
+		{"SLockUnsetCapacity", testSLockUnsetCapacity},
+		{"SLockRenewal", testSLockRenewal},
+		{"Expiry", testExpiry},
+		{"Refresh", testRefresh},
+		{"UnlockOwnership", testUnlockOwnership},
+		{"ForceUnlock", testForceUnlock},
+		{"ListLocks", testListLocks},
+		{"Policy", testPolicy},
+		{"PolicyTTLOnly", testPolicyTTLOnly},
+		{"PolicyDeleted", testPolicyDeleted},
+		{"BlockingCanceled", testBlockingCanceled},
+		{"ConcurrentXLock", testConcurrentXLock},
+		{"ConcurrentSLock", testConcurrentSLock},
+		{"History", testHistory},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, factory(t))
+		})
+	}
+}
+
+// LockName returns a lock name unique to the test, so that tests sharing a lock table don't interfere.
+func LockName(t *testing.T) string {
+	return fmt.Sprintf("pglocktest/%s/%d", t.Name(), time.Now().UnixNano())
+}
+
+// expire moves past the given TTL, either by advancing the backend's clock or by sleeping.
+func (b Backend) expire(ttlSeconds int) {
+	d := time.Duration(ttlSeconds)*time.Second + 100*time.Millisecond
+	if b.Advance != nil {
+		b.Advance(d)
+		return
+	}
+
+	time.Sleep(d)
+}
+
+func tryXLock(t *testing.T, client pglock.LockClient, name string, lockID string, ttlSeconds int) bool {
+	t.Helper()
+
+	result, err := client.TryXLock(context.Background(), pglock.TryXLockParams{
+		Name:       name,
+		LockID:     lockID,
+		TTLSeconds: ttlSeconds,
+	})
+	require.NoError(t, err)
+
+	return result.Acquired
+}
+
+func trySLock(t *testing.T, client pglock.LockClient, name string, lockID string, maxSharedLocks int, weight int) bool {
+	t.Helper()
+
+	result, err := client.TrySLock(context.Background(), pglock.TrySLockParams{
+		Name:           name,
+		LockID:         lockID,
+		TTLSeconds:     60,
+		MaxSharedLocks: maxSharedLocks,
+		Weight:         weight,
+	})
+	require.NoError(t, err)
+
+	return result.Acquired
+}
+
+func trySLockTTL(t *testing.T, client pglock.LockClient, name string, lockID string, ttlSeconds int) bool {
+	t.Helper()
+
+	result, err := client.TrySLock(context.Background(), pglock.TrySLockParams{
+		Name:           name,
+		LockID:         lockID,
+		TTLSeconds:     ttlSeconds,
+		MaxSharedLocks: -1,
+	})
+	require.NoError(t, err)
+
+	return result.Acquired
+}
+
+func unlock(t *testing.T, client pglock.LockClient, name string, lockID string) bool {
+	t.Helper()
+
+	result, err := client.Unlock(context.Background(), pglock.UnlockParams{
+		Name:   name,
+		LockID: lockID,
+	})
+	require.NoError(t, err)
+
+	return result.Released
+}
+
+func testXLockExcludesXLock(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	require.True(t, tryXLock(t, client, name, "lock_1", 60))
+	assert.False(t, tryXLock(t, client, name, "lock_2", 60))
+
+	// XLock은 재진입 불가
+	assert.False(t, tryXLock(t, client, name, "lock_1", 60))
+
+	require.True(t, unlock(t, client, name, "lock_1"))
+	assert.True(t, tryXLock(t, client, name, "lock_2", 60))
+}
+
+func testXLockExcludesSLock(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	require.True(t, tryXLock(t, client, name, "writer", 60))
+	assert.False(t, trySLock(t, client, name, "reader", -1, 1))
+
+	require.True(t, unlock(t, client, name, "writer"))
+	assert.True(t, trySLock(t, client, name, "reader", -1, 1))
+}
+
+func testSLockExcludesXLock(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	require.True(t, trySLock(t, client, name, "reader_1", -1, 1))
+	require.True(t, trySLock(t, client, name, "reader_2", -1, 1))
+	assert.False(t, tryXLock(t, client, name, "writer", 60))
+
+	// 모든 SLock이 해제되어야 XLock 가능
+	require.True(t, unlock(t, client, name, "reader_1"))
+	assert.False(t, tryXLock(t, client, name, "writer", 60))
+
+	require.True(t, unlock(t, client, name, "reader_2"))
+	assert.True(t, tryXLock(t, client, name, "writer", 60))
+}
+
+func testSLockCapacity(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	for i := 0; i < 3; i++ {
+		require.True(t, trySLock(t, client, name, fmt.Sprintf("reader_%d", i), 3, 1))
+	}
+	assert.False(t, trySLock(t, client, name, "reader_3", 3, 1))
+
+	require.True(t, unlock(t, client, name, "reader_0"))
+	assert.True(t, trySLock(t, client, name, "reader_3", 3, 1))
+}
+
+// testSLockUnsetCapacity tests that a MaxSharedLocks of 0 leaves the permits unlimited, even on a new lock.
+func testSLockUnsetCapacity(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	for i := 0; i < 3; i++ {
+		require.True(t, trySLock(t, client, name, fmt.Sprintf("reader_%d", i), 0, 1))
+	}
+	assert.True(t, trySLock(t, client, name, "reader_heavy", 0, 100))
+}
+
+func testSLockWeightedCapacity(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	// 최대치보다 큰 요청은 빈 락에서도 실패
+	assert.False(t, trySLock(t, client, name, "huge", 10, 11))
+
+	require.True(t, trySLock(t, client, name, "big", 10, 5))
+	require.True(t, trySLock(t, client, name, "medium", 10, 4))
+	assert.False(t, trySLock(t, client, name, "small", 10, 2))
+	assert.True(t, trySLock(t, client, name, "small", 10, 1))
+
+	describeResult, err := client.DescribeLock(context.Background(), pglock.DescribeLockParams{Name: name})
+	require.NoError(t, err)
+	require.True(t, describeResult.Found)
+
+	total := 0
+	for _, entry := range describeResult.Lock.SharedLocks {
+		total += entry.PermitWeight()
+	}
+	assert.Equal(t, 10, total)
+}
+
+func testSLockRenewal(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	require.True(t, trySLock(t, client, name, "reader_1", 2, 1))
+	require.True(t, trySLock(t, client, name, "reader_2", 2, 1))
+
+	// 가득 찬 상태에서도 보유자는 같은 weight로 갱신 가능
+	assert.True(t, trySLock(t, client, name, "reader_1", 2, 1))
+	// weight를 늘리는 갱신은 허용량을 다시 확인
+	assert.False(t, trySLock(t, client, name, "reader_1", 2, 2))
+
+	describeResult, err := client.DescribeLock(context.Background(), pglock.DescribeLockParams{Name: name})
+	require.NoError(t, err)
+	assert.Len(t, describeResult.Lock.SharedLocks, 2)
+}
+
+func testExpiry(t *testing.T, backend Backend) {
+	client := backend.Client
+	xName := LockName(t) + "/x"
+	sName := LockName(t) + "/s"
+
+	require.True(t, tryXLock(t, client, xName, "holder", 1))
+	require.True(t, trySLockTTL(t, client, sName, "holder", 1))
+	assert.False(t, tryXLock(t, client, xName, "other", 1))
+	assert.False(t, tryXLock(t, client, sName, "other", 1))
+
+	backend.expire(1)
+
+	// 만료된 보유자는 조회되지 않고, 다른 보유자가 획득 가능
+	describeResult, err := client.DescribeLock(context.Background(), pglock.DescribeLockParams{Name: xName})
+	require.NoError(t, err)
+	assert.Empty(t, describeResult.Lock.XLockID)
+
+	assert.True(t, tryXLock(t, client, xName, "other", 60))
+	assert.True(t, tryXLock(t, client, sName, "other", 60))
+}
+
+func testRefresh(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	name := LockName(t)
+
+	acquireResult, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: name, LockID: "holder", TTLSeconds: 1})
+	require.NoError(t, err)
+	require.True(t, acquireResult.Acquired)
+
+	// 보유자만 갱신 가능
+	otherResult, err := client.Refresh(ctx, pglock.RefreshParams{Name: name, LockID: "other", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, otherResult.Refreshed)
+
+	refreshResult, err := client.Refresh(ctx, pglock.RefreshParams{Name: name, LockID: "holder", TTLSeconds: 3})
+	require.NoError(t, err)
+	require.True(t, refreshResult.Refreshed)
+	assert.True(t, refreshResult.ExpiresAt.After(acquireResult.ExpiresAt))
+
+	// 원래 TTL이 지나도 갱신된 락은 유지
+	backend.expire(1)
+	assert.False(t, tryXLock(t, client, name, "other", 60))
+
+	// 만료된 락은 갱신되지 않음
+	backend.expire(2)
+	lostResult, err := client.Refresh(ctx, pglock.RefreshParams{Name: name, LockID: "holder", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, lostResult.Refreshed)
+
+	missingResult, err := client.Refresh(ctx, pglock.RefreshParams{Name: LockName(t) + "/missing", LockID: "holder", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, missingResult.Refreshed)
+}
+
+func testUnlockOwnership(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	assert.False(t, unlock(t, client, LockName(t)+"/missing", "holder"))
+
+	require.True(t, tryXLock(t, client, name, "holder", 60))
+
+	// 다른 LockID로는 해제할 수 없음
+	assert.False(t, unlock(t, client, name, "other"))
+	assert.False(t, tryXLock(t, client, name, "other", 60))
+
+	assert.True(t, unlock(t, client, name, "holder"))
+	assert.False(t, unlock(t, client, name, "holder"))
+}
+
+func testForceUnlock(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	name := LockName(t)
+
+	require.True(t, trySLock(t, client, name, "reader_1", -1, 1))
+	require.True(t, trySLock(t, client, name, "reader_2", -1, 1))
+
+	result, err := client.ForceUnlock(ctx, pglock.ForceUnlockParams{Name: name})
+	require.NoError(t, err)
+	assert.True(t, result.Released)
+	assert.Empty(t, result.XLockID)
+	assert.ElementsMatch(t, []string{"reader_1", "reader_2"}, result.SharedLockIDs)
+
+	assert.True(t, tryXLock(t, client, name, "writer", 60))
+
+	missingResult, err := client.ForceUnlock(ctx, pglock.ForceUnlockParams{Name: LockName(t) + "/missing"})
+	require.NoError(t, err)
+	assert.False(t, missingResult.Released)
+}
+
+// testListLocks tests that ListLocks skips locks without a live holder before applying the limit.
+func testListLocks(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	prefix := LockName(t) + "/"
+
+	names := func(locks []pglock.LockInfo) []string {
+		result := []string{}
+		for _, lock := range locks {
+			result = append(result, strings.TrimPrefix(lock.Name, prefix))
+		}
+		return result
+	}
+
+	// 1. a, d, e는 보유 중이고 b는 해제됨, c는 세션이 닫혀 TTL과 무관하게 해제됨
+	session, err := client.OpenSession(ctx, pglock.OpenSessionParams{TTLSeconds: 60})
+	require.NoError(t, err)
+
+	require.True(t, tryXLock(t, client, prefix+"a", "holder", 60))
+	require.True(t, tryXLock(t, client, prefix+"b", "holder", 60))
+	require.True(t, unlock(t, client, prefix+"b", "holder"))
+	xResult, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: prefix + "c", LockID: "holder", TTLSeconds: 600, SessionID: session.SessionID})
+	require.NoError(t, err)
+	require.True(t, xResult.Acquired)
+	require.True(t, trySLock(t, client, prefix+"d", "holder", -1, 1))
+	require.True(t, tryXLock(t, client, prefix+"e", "holder", 60))
+
+	_, err = client.CloseSession(ctx, pglock.CloseSessionParams{SessionID: session.SessionID})
+	require.NoError(t, err)
+
+	// 2. Limit은 보유자가 있는 락에 적용됨
+	listResult, err := client.ListLocks(ctx, pglock.ListLocksParams{Prefix: prefix, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "d"}, names(listResult.Locks))
+
+	listResult, err = client.ListLocks(ctx, pglock.ListLocksParams{Prefix: prefix})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "d", "e"}, names(listResult.Locks))
+
+	// 3. IncludeFree면 보유자가 없는 락도 포함
+	listResult, err = client.ListLocks(ctx, pglock.ListLocksParams{Prefix: prefix, IncludeFree: true, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, names(listResult.Locks))
+	assert.Empty(t, listResult.Locks[1].XLockID)
+	assert.Empty(t, listResult.Locks[2].XLockID)
+}
+
+func testPolicy(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	prefix := LockName(t) + "/"
+	name := prefix + "resource"
+
+	_, err := client.SetLockPolicy(ctx, pglock.SetLockPolicyParams{
+		Name:           prefix,
+		Prefix:         true,
+		MaxSharedLocks: 1,
+		MaxTTLSeconds:  30,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = client.DeleteLockPolicy(context.Background(), pglock.DeleteLockPolicyParams{Name: prefix, Prefix: true})
+	})
+
+	// 호출자가 넘긴 MaxSharedLocks보다 정책이 우선
+	require.True(t, trySLock(t, client, name, "reader_1", 10, 1))
+	assert.False(t, trySLock(t, client, name, "reader_2", 10, 1))
+
+	// TTL은 정책의 최대치로 제한 (정책이 없는 이름과 비교)
+	limitedResult, err := client.Refresh(ctx, pglock.RefreshParams{Name: name, LockID: "reader_1", TTLSeconds: 3600})
+	require.NoError(t, err)
+	require.True(t, limitedResult.Refreshed)
+
+	freeResult, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: LockName(t) + "/free", LockID: "holder", TTLSeconds: 3600})
+	require.NoError(t, err)
+	require.True(t, freeResult.Acquired)
+	assert.Greater(t, freeResult.ExpiresAt.Sub(limitedResult.ExpiresAt), 50*time.Minute)
+
+	policyResult, err := client.GetLockPolicy(ctx, pglock.GetLockPolicyParams{Name: name})
+	require.NoError(t, err)
+	assert.True(t, policyResult.Found)
+	assert.Equal(t, 1, policyResult.Policy.MaxSharedLocks)
+}
+
+// testPolicyDeleted tests that a lock created under a policy goes back to the caller's permit limit once the policy is deleted.
+func testPolicyDeleted(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	name := LockName(t)
+
+	_, err := client.SetLockPolicy(ctx, pglock.SetLockPolicyParams{Name: name, MaxSharedLocks: 1})
+	require.NoError(t, err)
+
+	// 1. 정책이 있는 동안에는 정책의 제한 적용
+	require.True(t, trySLock(t, client, name, "reader_1", 3, 1))
+	assert.False(t, trySLock(t, client, name, "reader_2", 3, 1))
+
+	// 2. 정책을 삭제하면 행에 저장된 호출자의 제한이 다시 적용
+	_, err = client.DeleteLockPolicy(ctx, pglock.DeleteLockPolicyParams{Name: name})
+	require.NoError(t, err)
+
+	require.True(t, trySLock(t, client, name, "reader_2", 3, 1))
+	require.True(t, trySLock(t, client, name, "reader_3", 3, 1))
+	assert.False(t, trySLock(t, client, name, "reader_4", 3, 1))
+
+	describeResult, err := client.DescribeLock(ctx, pglock.DescribeLockParams{Name: name})
+	require.NoError(t, err)
+	assert.Equal(t, 3, describeResult.Lock.MaxSharedLocks)
+}
+
+// testPolicyTTLOnly tests that a policy without MaxSharedLocks keeps the caller's permit limit.
+func testPolicyTTLOnly(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	prefix := LockName(t) + "/"
+	name := prefix + "resource"
+
+	_, err := client.SetLockPolicy(ctx, pglock.SetLockPolicyParams{
+		Name:              prefix,
+		Prefix:            true,
+		DefaultTTLSeconds: 30,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = client.DeleteLockPolicy(context.Background(), pglock.DeleteLockPolicyParams{Name: prefix, Prefix: true})
+	})
+
+	// SLock은 호출자의 MaxSharedLocks로 제한되고, TTL만 정책의 기본값을 사용
+	result, err := client.TrySLock(ctx, pglock.TrySLockParams{Name: name, LockID: "reader_1", MaxSharedLocks: 2})
+	require.NoError(t, err)
+	require.True(t, result.Acquired)
+	assert.True(t, trySLock(t, client, name, "reader_2", 2, 1))
+	assert.False(t, trySLock(t, client, name, "reader_3", 2, 1))
+
+	// 제한 없는 SLock도 정책에 막히지 않음
+	assert.True(t, trySLock(t, client, prefix+"unlimited", "reader_1", -1, 1))
+
+	// 음수 제한은 -1만 허용
+	_, err = client.SetLockPolicy(ctx, pglock.SetLockPolicyParams{Name: prefix + "invalid", MaxSharedLocks: -2})
+	assert.Error(t, err)
+}
+
+func testBlockingCanceled(t *testing.T, backend Backend) {
+	client := backend.Client
+	name := LockName(t)
+
+	require.True(t, tryXLock(t, client, name, "holder", 60))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := client.XLock(ctx, pglock.XLockParams{
+		Name:             name,
+		LockID:           "waiter",
+		TTLSeconds:       60,
+		IntervalDuration: 20 * time.Millisecond,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = client.SLock(ctx, pglock.SLockParams{
+		Name:             name,
+		LockID:           "waiter",
+		TTLSeconds:       60,
+		MaxSharedLocks:   -1,
+		IntervalDuration: 20 * time.Millisecond,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// 취소된 대기자는 락을 남기지 않음
+	assert.False(t, unlock(t, client, name, "waiter"))
+}
+
+// concurrencyCounter tracks how many goroutines are inside a critical section at once.
+type concurrencyCounter struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (c *concurrencyCounter) enter() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.current++
+	c.max = max(c.max, c.current)
+}
+
+func (c *concurrencyCounter) leave() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.current--
+}
+
+func testConcurrentXLock(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	name := LockName(t)
+
+	var wg sync.WaitGroup
+	counter := &concurrencyCounter{}
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			lockID := fmt.Sprintf("xlock_%d", id)
+			_, err := client.XLock(ctx, pglock.XLockParams{
+				Name:             name,
+				LockID:           lockID,
+				TTLSeconds:       60,
+				IntervalDuration: 10 * time.Millisecond,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			counter.enter()
+			time.Sleep(20 * time.Millisecond)
+			counter.leave()
+
+			_, err = client.Unlock(ctx, pglock.UnlockParams{Name: name, LockID: lockID})
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1, counter.max)
+}
+
+func testConcurrentSLock(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	client := backend.Client
+	name := LockName(t)
+
+	var wg sync.WaitGroup
+	counter := &concurrencyCounter{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			lockID := fmt.Sprintf("slock_%d", id)
+			_, err := client.SLock(ctx, pglock.SLockParams{
+				Name:             name,
+				LockID:           lockID,
+				TTLSeconds:       60,
+				MaxSharedLocks:   3,
+				IntervalDuration: 10 * time.Millisecond,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			counter.enter()
+			time.Sleep(20 * time.Millisecond)
+			counter.leave()
+
+			_, err = client.Unlock(ctx, pglock.UnlockParams{Name: name, LockID: lockID})
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	wg.Wait()
+	assert.LessOrEqual(t, counter.max, 3)
+	assert.Positive(t, counter.max)
+}
+
+func testHistory(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	recorder := NewRecorder(backend.Clock)
+	client := recorder.Wrap(backend.Client)
+	name := LockName(t)
+
+	// 행을 XLock이 먼저 만들어도 같은 허용량이 적용되도록 정책으로 고정
+	_, err := client.SetLockPolicy(ctx, pglock.SetLockPolicyParams{Name: name, MaxSharedLocks: 4})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = client.DeleteLockPolicy(context.Background(), pglock.DeleteLockPolicyParams{Name: name})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			lockID := fmt.Sprintf("holder_%d", id)
+			for round := 0; round < 5; round++ {
+				var acquired bool
+				if (id+round)%3 == 0 {
+					result, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: name, LockID: lockID, TTLSeconds: 60})
+					if !assert.NoError(t, err) {
+						return
+					}
+					acquired = result.Acquired
+				} else {
+					result, err := client.TrySLock(ctx, pglock.TrySLockParams{
+						Name:           name,
+						LockID:         lockID,
+						TTLSeconds:     60,
+						MaxSharedLocks: 4,
+						Weight:         1 + id%2,
+					})
+					if !assert.NoError(t, err) {
+						return
+					}
+					acquired = result.Acquired
+				}
+				if !acquired {
+					time.Sleep(time.Millisecond)
+					continue
+				}
+
+				_, err := client.Refresh(ctx, pglock.RefreshParams{Name: name, LockID: lockID, TTLSeconds: 60})
+				assert.NoError(t, err)
+
+				time.Sleep(2 * time.Millisecond)
+
+				_, err = client.Unlock(ctx, pglock.UnlockParams{Name: name, LockID: lockID})
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	assert.NoError(t, recorder.Check())
+}
@@ -0,0 +1,52 @@
+package pglocktest
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/myyrakle/pglock"
+	"github.com/myyrakle/pglock/remote"
+)
+
+func TestConformance_Memory(t *testing.T) {
+	RunConformance(t, func(t *testing.T) Backend {
+		clock := pglock.NewManualClock(time.Now())
+
+		return Backend{
+			Client:  pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{Clock: clock}),
+			Advance: clock.Advance,
+			Clock:   clock,
+		}
+	})
+}
+
+func TestConformance_Remote(t *testing.T) {
+	RunConformance(t, func(t *testing.T) Backend {
+		clock := pglock.NewManualClock(time.Now())
+		server := httptest.NewServer(remote.NewHandler(
+			pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{Clock: clock}),
+			remote.HandlerOptions{AdminToken: "admin"},
+		))
+		t.Cleanup(server.Close)
+
+		return Backend{
+			Client:  remote.NewLockClient(remote.ClientOptions{BaseURL: server.URL, Token: "admin"}),
+			Advance: clock.Advance,
+			Clock:   clock,
+		}
+	})
+}
+
+func TestConformance_Postgres(t *testing.T) {
+	client := pglock.NewLockClient(pglock.LockClientOptions{
+		DatabaseURL: "postgres://postgres@localhost:5432/postgres?sslmode=disable",
+	})
+	if err := client.Initialize(); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	RunConformance(t, func(t *testing.T) Backend {
+		return Backend{Client: client}
+	})
+}
@@ -0,0 +1,516 @@
+package pglocktest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myyrakle/pglock"
+)
+
+// OperationKind is the kind of a recorded lock operation
+type OperationKind string
+
+const (
+	OperationAcquire OperationKind = "acquire" // TryXLock, XLock, TrySLock or SLock
+	OperationRefresh OperationKind = "refresh" // Refresh
+	OperationRelease OperationKind = "release" // Unlock
+)
+
+// Instant is a point in a recorded history.
+// Seq orders the instants of all goroutines; Time is read from the recorder's clock and is compared with lease expiry.
+type Instant struct {
+	Seq  int64
+	Time time.Time
+}
+
+// Operation is a lock operation recorded between its call and its return
+type Operation struct {
+	Kind           OperationKind
+	Name           string
+	LockID         string
+	Mode           pglock.LockMode // Mode of an acquire ("" for refresh and release)
+	Weight         int             // Permits taken by a shared acquire
+	MaxSharedLocks int             // Capacity passed to a shared acquire (-1 or 0 for unlimited)
+	Succeeded      bool            // Whether the lock was acquired, refreshed or released
+	ExpiresAt      time.Time       // Lease expiry returned by a successful acquire or refresh
+	Err            error           // Error returned by the operation, if any
+	Call           Instant         // When the operation was invoked
+	Return         Instant         // When the operation returned
+}
+
+func (op Operation) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[%d..%d] %s %s", op.Call.Seq, op.Return.Seq, op.LockID, op.Kind)
+	if op.Mode != "" {
+		fmt.Fprintf(&b, " %s", op.Mode)
+	}
+	if op.Mode == pglock.LockModeShared {
+		fmt.Fprintf(&b, " weight=%d max=%d", op.Weight, op.MaxSharedLocks)
+	}
+	fmt.Fprintf(&b, " succeeded=%t", op.Succeeded)
+	if !op.ExpiresAt.IsZero() {
+		fmt.Fprintf(&b, " expires_at=%s", op.ExpiresAt.Format(time.RFC3339Nano))
+	}
+	if op.Err != nil {
+		fmt.Fprintf(&b, " err=%v", op.Err)
+	}
+
+	return b.String()
+}
+
+// Recorder records the lock operations made through the clients it wraps.
+// It is safe for concurrent use.
+type Recorder struct {
+	clock pglock.Clock
+
+	mu         sync.Mutex
+	lastSeq    int64
+	operations []Operation
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewRecorder returns a Recorder that timestamps operations with clock.
+// The clock must be the one the lock client uses for expiry (nil for the system clock).
+func NewRecorder(clock pglock.Clock) *Recorder {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &Recorder{clock: clock}
+}
+
+func (r *Recorder) now() Instant {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastSeq++
+	return Instant{Seq: r.lastSeq, Time: r.clock.Now()}
+}
+
+func (r *Recorder) record(op Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.operations = append(r.operations, op)
+}
+
+// Operations returns the recorded operations in the order they were called.
+func (r *Recorder) Operations() []Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	operations := slices.Clone(r.operations)
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Call.Seq < operations[j].Call.Seq
+	})
+
+	return operations
+}
+
+// Check verifies the recorded history with CheckHistory.
+func (r *Recorder) Check() error {
+	return CheckHistory(r.Operations())
+}
+
+// Wrap returns a LockClient that records every acquire, refresh and release made through client.
+// Other methods are passed through unrecorded.
+func (r *Recorder) Wrap(client pglock.LockClient) pglock.LockClient {
+	return &recordingLockClient{LockClient: client, recorder: r}
+}
+
+type recordingLockClient struct {
+	pglock.LockClient
+
+	recorder *Recorder
+}
+
+func (c *recordingLockClient) TryXLock(ctx context.Context, params pglock.TryXLockParams) (pglock.TryXLockResult, error) {
+	call := c.recorder.now()
+	result, err := c.LockClient.TryXLock(ctx, params)
+
+	c.recorder.record(Operation{
+		Kind:      OperationAcquire,
+		Name:      params.Name,
+		LockID:    params.LockID,
+		Mode:      pglock.LockModeExclusive,
+		Succeeded: err == nil && result.Acquired,
+		ExpiresAt: result.ExpiresAt,
+		Err:       err,
+		Call:      call,
+		Return:    c.recorder.now(),
+	})
+
+	return result, err
+}
+
+func (c *recordingLockClient) XLock(ctx context.Context, params pglock.XLockParams) (pglock.XLockResult, error) {
+	call := c.recorder.now()
+	result, err := c.LockClient.XLock(ctx, params)
+
+	c.recorder.record(Operation{
+		Kind:      OperationAcquire,
+		Name:      params.Name,
+		LockID:    params.LockID,
+		Mode:      pglock.LockModeExclusive,
+		Succeeded: err == nil,
+		ExpiresAt: result.ExpiresAt,
+		Err:       err,
+		Call:      call,
+		Return:    c.recorder.now(),
+	})
+
+	return result, err
+}
+
+func (c *recordingLockClient) TrySLock(ctx context.Context, params pglock.TrySLockParams) (pglock.TrySLockResult, error) {
+	call := c.recorder.now()
+	result, err := c.LockClient.TrySLock(ctx, params)
+
+	c.recorder.record(Operation{
+		Kind:           OperationAcquire,
+		Name:           params.Name,
+		LockID:         params.LockID,
+		Mode:           pglock.LockModeShared,
+		Weight:         max(params.Weight, 1),
+		MaxSharedLocks: params.MaxSharedLocks,
+		Succeeded:      err == nil && result.Acquired,
+		ExpiresAt:      result.ExpiresAt,
+		Err:            err,
+		Call:           call,
+		Return:         c.recorder.now(),
+	})
+
+	return result, err
+}
+
+func (c *recordingLockClient) SLock(ctx context.Context, params pglock.SLockParams) (pglock.SLockResult, error) {
+	call := c.recorder.now()
+	result, err := c.LockClient.SLock(ctx, params)
+
+	c.recorder.record(Operation{
+		Kind:           OperationAcquire,
+		Name:           params.Name,
+		LockID:         params.LockID,
+		Mode:           pglock.LockModeShared,
+		Weight:         max(params.Weight, 1),
+		MaxSharedLocks: params.MaxSharedLocks,
+		Succeeded:      err == nil,
+		ExpiresAt:      result.ExpiresAt,
+		Err:            err,
+		Call:           call,
+		Return:         c.recorder.now(),
+	})
+
+	return result, err
+}
+
+func (c *recordingLockClient) Refresh(ctx context.Context, params pglock.RefreshParams) (pglock.RefreshResult, error) {
+	call := c.recorder.now()
+	result, err := c.LockClient.Refresh(ctx, params)
+
+	c.recorder.record(Operation{
+		Kind:      OperationRefresh,
+		Name:      params.Name,
+		LockID:    params.LockID,
+		Succeeded: err == nil && result.Refreshed,
+		ExpiresAt: result.ExpiresAt,
+		Err:       err,
+		Call:      call,
+		Return:    c.recorder.now(),
+	})
+
+	return result, err
+}
+
+func (c *recordingLockClient) Unlock(ctx context.Context, params pglock.UnlockParams) (pglock.UnlockResult, error) {
+	call := c.recorder.now()
+	result, err := c.LockClient.Unlock(ctx, params)
+
+	c.recorder.record(Operation{
+		Kind:      OperationRelease,
+		Name:      params.Name,
+		LockID:    params.LockID,
+		Succeeded: err == nil && result.Released,
+		Err:       err,
+		Call:      call,
+		Return:    c.recorder.now(),
+	})
+
+	return result, err
+}
+
+// Violation describes a part of a history that breaks the lock invariants.
+type Violation struct {
+	Name       string      // Lock Name
+	Reason     string      // Which invariant was broken
+	Operations []Operation // The smallest set of operations that shows the violation, in call order
+}
+
+func (v *Violation) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "pglocktest: %s on lock %q:", v.Reason, v.Name)
+	for _, op := range v.Operations {
+		fmt.Fprintf(&b, "\n\t%s", op)
+	}
+
+	return b.String()
+}
+
+// hold is a span of a history during which a LockID definitely held a lock.
+// It starts when the acquire returned and ends when the release was called or the lease expired.
+type hold struct {
+	name           string
+	mode           pglock.LockMode
+	weight         int
+	maxSharedLocks int
+	expiresAt      time.Time
+	releasedAt     int64       // Seq of the call that ended the hold (0 if never released)
+	operations     []Operation // The acquire and the refreshes that extended the lease
+
+	start int64 // Seq from which the lock was definitely held
+	end   int64 // Seq from which the lock may no longer be held (exclusive)
+}
+
+// CheckHistory verifies mutual exclusion and shared capacity over a recorded history.
+// A lock counts as held from the return of a successful acquire until the call of the release
+// or the lease expiry, whichever comes first, so only overlaps that happened in every possible
+// linearization are reported. Capacity is the MaxSharedLocks passed by the acquire that
+// completes the overlap, so callers of the same name are expected to pass the same value.
+// It returns a *Violation holding the minimal violating interleaving, or nil.
+func CheckHistory(operations []Operation) error {
+	operations = slices.Clone(operations)
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Call.Seq < operations[j].Call.Seq
+	})
+
+	holds, violation := buildHolds(operations)
+	if violation != nil {
+		return violation
+	}
+
+	// 보유 구간의 끝 = 해제 호출 또는 만료 이후 처음 기록된 시점
+	instants := []Instant{}
+	for _, op := range operations {
+		instants = append(instants, op.Call, op.Return)
+	}
+	sort.Slice(instants, func(i, j int) bool {
+		return instants[i].Seq < instants[j].Seq
+	})
+	for _, h := range holds {
+		h.end = expirySeq(instants, h.expiresAt)
+		if h.releasedAt != 0 && h.releasedAt < h.end {
+			h.end = h.releasedAt
+		}
+	}
+
+	byName := map[string][]*hold{}
+	names := []string{}
+	for _, h := range holds {
+		if h.end <= h.start {
+			continue
+		}
+		if _, ok := byName[h.name]; !ok {
+			names = append(names, h.name)
+		}
+		byName[h.name] = append(byName[h.name], h)
+	}
+	sort.Strings(names)
+
+	var minimal *Violation
+	for _, name := range names {
+		violation := checkHolds(name, byName[name])
+		if violation != nil && (minimal == nil || len(violation.Operations) < len(minimal.Operations)) {
+			minimal = violation
+		}
+	}
+	if minimal != nil {
+		return minimal
+	}
+
+	return nil
+}
+
+// buildHolds replays the operations of each LockID and returns the spans during which it held a lock.
+func buildHolds(operations []Operation) ([]*hold, *Violation) {
+	type holderKey struct {
+		name   string
+		lockID string
+	}
+
+	holds := []*hold{}
+	current := map[holderKey]*hold{}
+	uncertain := map[holderKey]bool{} // acquire failed with an error, so the lock may or may not be held
+
+	for _, op := range operations {
+		key := holderKey{name: op.Name, lockID: op.LockID}
+		h := current[key]
+
+		// 만료된 보유는 더 이상 현재 보유가 아님
+		if h != nil && !op.Call.Time.Before(h.expiresAt) {
+			delete(current, key)
+			h = nil
+		}
+
+		switch op.Kind {
+		case OperationAcquire:
+			if op.Err != nil {
+				uncertain[key] = true
+			}
+			if !op.Succeeded {
+				continue
+			}
+			delete(uncertain, key)
+			// 같은 보유자의 SLock 갱신은 이전 구간을 호출 시점에 끝내고 새 구간을 시작
+			if h != nil {
+				h.releasedAt = op.Call.Seq
+			}
+
+			h = &hold{
+				name:           op.Name,
+				mode:           op.Mode,
+				weight:         max(op.Weight, 1),
+				maxSharedLocks: op.MaxSharedLocks,
+				expiresAt:      op.ExpiresAt,
+				operations:     []Operation{op},
+				start:          op.Return.Seq,
+			}
+			holds = append(holds, h)
+			current[key] = h
+
+		case OperationRefresh:
+			if !op.Succeeded {
+				continue
+			}
+			if h == nil && uncertain[key] {
+				continue
+			}
+			if h == nil {
+				return nil, &Violation{
+					Name:       op.Name,
+					Reason:     "lease refreshed while not held",
+					Operations: append(priorOperations(operations, key.name, key.lockID, op.Call.Seq), op),
+				}
+			}
+
+			h.expiresAt = op.ExpiresAt
+			h.operations = append(h.operations, op)
+
+		case OperationRelease:
+			delete(uncertain, key)
+			if h != nil {
+				h.releasedAt = op.Call.Seq
+				delete(current, key)
+			}
+		}
+	}
+
+	return holds, nil
+}
+
+// priorOperations returns the successful acquire and refresh operations of a LockID called before seq.
+func priorOperations(operations []Operation, name string, lockID string, seq int64) []Operation {
+	prior := []Operation{}
+	for _, op := range operations {
+		if op.Name == name && op.LockID == lockID && op.Call.Seq < seq && op.Succeeded && op.Kind != OperationRelease {
+			prior = append(prior, op)
+		}
+	}
+
+	return prior
+}
+
+// expirySeq returns the Seq of the first instant at or after expiresAt, which ends a hold.
+// A hold whose lease outlives the history never ends.
+func expirySeq(instants []Instant, expiresAt time.Time) int64 {
+	index := sort.Search(len(instants), func(i int) bool {
+		return !instants[i].Time.Before(expiresAt)
+	})
+	if index == len(instants) {
+		return int64(1) << 62
+	}
+
+	return instants[index].Seq
+}
+
+// checkHolds looks for overlapping holds of a single lock name.
+func checkHolds(name string, holds []*hold) *Violation {
+	sort.Slice(holds, func(i, j int) bool {
+		return holds[i].start < holds[j].start
+	})
+
+	var minimal *Violation
+	active := []*hold{}
+	for _, h := range holds {
+		// 새 구간이 시작되기 전에 끝난 구간 제거
+		stillActive := active[:0]
+		for _, a := range active {
+			if a.end > h.start {
+				stillActive = append(stillActive, a)
+			}
+		}
+		active = stillActive
+
+		var violation *Violation
+		for _, a := range active {
+			if a.mode == pglock.LockModeExclusive || h.mode == pglock.LockModeExclusive {
+				violation = newViolation(name, "exclusive lock held concurrently", h.start, a, h)
+				break
+			}
+		}
+
+		if violation == nil && h.mode == pglock.LockModeShared && h.maxSharedLocks > 0 {
+			// 허용량을 넘기는 가장 작은 보유자 집합
+			overlapping := append(slices.Clone(active), h)
+			sort.SliceStable(overlapping, func(i, j int) bool {
+				return overlapping[i].weight > overlapping[j].weight
+			})
+
+			total := 0
+			for i, o := range overlapping {
+				total += o.weight
+				if total > h.maxSharedLocks {
+					reason := fmt.Sprintf("shared capacity exceeded (%d > %d)", total, h.maxSharedLocks)
+					violation = newViolation(name, reason, h.start, overlapping[:i+1]...)
+					break
+				}
+			}
+		}
+
+		if violation != nil && (minimal == nil || len(violation.Operations) < len(minimal.Operations)) {
+			minimal = violation
+		}
+
+		active = append(active, h)
+	}
+
+	return minimal
+}
+
+// newViolation reports the operations of the holds that were called before the overlap at seq.
+func newViolation(name string, reason string, seq int64, holds ...*hold) *Violation {
+	operations := []Operation{}
+	for _, h := range holds {
+		for _, op := range h.operations {
+			if op.Call.Seq <= seq {
+				operations = append(operations, op)
+			}
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Call.Seq < operations[j].Call.Seq
+	})
+
+	return &Violation{Name: name, Reason: reason, Operations: operations}
+}
@@ -0,0 +1,155 @@
+package pglocktest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/myyrakle/pglock"
+)
+
+var historyStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// at returns the instant with the given sequence number, one second apart
+func at(seq int64) Instant {
+	return Instant{Seq: seq, Time: historyStart.Add(time.Duration(seq) * time.Second)}
+}
+
+func xAcquire(lockID string, call int64, ret int64, ttl time.Duration) Operation {
+	return Operation{
+		Kind:      OperationAcquire,
+		Name:      "test_history",
+		LockID:    lockID,
+		Mode:      pglock.LockModeExclusive,
+		Succeeded: true,
+		ExpiresAt: at(call).Time.Add(ttl),
+		Call:      at(call),
+		Return:    at(ret),
+	}
+}
+
+func sAcquire(lockID string, call int64, ret int64, weight int, maxSharedLocks int) Operation {
+	return Operation{
+		Kind:           OperationAcquire,
+		Name:           "test_history",
+		LockID:         lockID,
+		Mode:           pglock.LockModeShared,
+		Weight:         weight,
+		MaxSharedLocks: maxSharedLocks,
+		Succeeded:      true,
+		ExpiresAt:      at(call).Time.Add(time.Hour),
+		Call:           at(call),
+		Return:         at(ret),
+	}
+}
+
+func release(lockID string, call int64, ret int64) Operation {
+	return Operation{
+		Kind:      OperationRelease,
+		Name:      "test_history",
+		LockID:    lockID,
+		Succeeded: true,
+		Call:      at(call),
+		Return:    at(ret),
+	}
+}
+
+// TestCheckHistory_Valid tests histories that some linearization explains
+func TestCheckHistory_Valid(t *testing.T) {
+	// 1. 해제 후 획득
+	assert.NoError(t, CheckHistory([]Operation{
+		xAcquire("lock_1", 1, 2, time.Hour),
+		release("lock_1", 3, 4),
+		xAcquire("lock_2", 5, 6, time.Hour),
+	}))
+
+	// 2. 해제와 겹친 획득 - 해제가 먼저 적용되었을 수 있음
+	assert.NoError(t, CheckHistory([]Operation{
+		xAcquire("lock_1", 1, 2, time.Hour),
+		release("lock_1", 3, 6),
+		xAcquire("lock_2", 4, 5, time.Hour),
+	}))
+
+	// 3. 만료 후 획득
+	assert.NoError(t, CheckHistory([]Operation{
+		xAcquire("lock_1", 1, 2, 2*time.Second),
+		xAcquire("lock_2", 4, 5, time.Hour),
+	}))
+
+	// 4. 허용량 안의 SLock
+	assert.NoError(t, CheckHistory([]Operation{
+		sAcquire("lock_1", 1, 2, 2, 3),
+		sAcquire("lock_2", 3, 4, 1, 3),
+	}))
+}
+
+// TestCheckHistory_MutualExclusion tests that overlapping exclusive holds are reported with only the operations involved
+func TestCheckHistory_MutualExclusion(t *testing.T) {
+	err := CheckHistory([]Operation{
+		sAcquire("reader", 1, 2, 1, -1),
+		release("reader", 3, 4),
+		xAcquire("lock_1", 5, 6, time.Hour),
+		xAcquire("lock_2", 7, 8, time.Hour),
+		release("lock_1", 9, 10),
+	})
+
+	var violation *Violation
+	require.ErrorAs(t, err, &violation)
+	assert.Equal(t, "test_history", violation.Name)
+	assert.Contains(t, violation.Reason, "exclusive")
+	require.Len(t, violation.Operations, 2)
+	assert.Equal(t, "lock_1", violation.Operations[0].LockID)
+	assert.Equal(t, "lock_2", violation.Operations[1].LockID)
+}
+
+// TestCheckHistory_Capacity tests that the smallest set of shared holders exceeding the capacity is reported
+func TestCheckHistory_Capacity(t *testing.T) {
+	err := CheckHistory([]Operation{
+		sAcquire("small_1", 1, 2, 1, 5),
+		sAcquire("small_2", 3, 4, 1, 5),
+		sAcquire("big_1", 5, 6, 3, 5),
+		sAcquire("big_2", 7, 8, 3, 5),
+	})
+
+	var violation *Violation
+	require.ErrorAs(t, err, &violation)
+	assert.Contains(t, violation.Reason, "capacity")
+	require.Len(t, violation.Operations, 2)
+	assert.Equal(t, "big_1", violation.Operations[0].LockID)
+	assert.Equal(t, "big_2", violation.Operations[1].LockID)
+}
+
+// TestCheckHistory_Lease tests lease expiry semantics: refresh extends a hold, and an expired lease cannot be refreshed
+func TestCheckHistory_Lease(t *testing.T) {
+	refresh := Operation{
+		Kind:      OperationRefresh,
+		Name:      "test_history",
+		LockID:    "lock_1",
+		Succeeded: true,
+		ExpiresAt: at(3).Time.Add(time.Hour),
+		Call:      at(3),
+		Return:    at(4),
+	}
+
+	// 1. 갱신된 락을 원래 만료 시각 이후에 가져가면 위반
+	err := CheckHistory([]Operation{
+		xAcquire("lock_1", 1, 2, 5*time.Second),
+		refresh,
+		xAcquire("lock_2", 8, 9, time.Hour),
+	})
+	var violation *Violation
+	require.ErrorAs(t, err, &violation)
+	assert.Len(t, violation.Operations, 3)
+
+	// 2. 만료된 락의 갱신 성공은 위반
+	refresh.Call = at(10)
+	refresh.Return = at(11)
+	err = CheckHistory([]Operation{
+		xAcquire("lock_1", 1, 2, 5*time.Second),
+		refresh,
+	})
+	require.ErrorAs(t, err, &violation)
+	assert.Contains(t, violation.Reason, "refreshed")
+}
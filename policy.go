@@ -0,0 +1,191 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+func (c *lockClient) createLockPolicyTable(ctx context.Context) error {
+	tableName := c.options.LockPolicyTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			name TEXT NOT NULL,
+			is_prefix BOOLEAN NOT NULL DEFAULT FALSE,
+			max_shared_locks INT NOT NULL DEFAULT 0,
+			default_ttl_seconds INT NOT NULL DEFAULT 0,
+			max_ttl_seconds INT NOT NULL DEFAULT 0,
+			PRIMARY KEY (name, is_prefix)
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+
+	return err
+}
+
+// LockPolicy is a persisted configuration applied by every acquire call for matching lock names.
+type LockPolicy struct {
+	Name              string // Lock name (or name prefix when Prefix is true) the policy applies to
+	Prefix            bool   // Whether Name is matched as a prefix of lock names
+	MaxSharedLocks    int    // Maximum number of shared permits allowed (-1 for unlimited, 0: the caller's MaxSharedLocks applies)
+	DefaultTTLSeconds int    // TTL used when the caller passes TTLSeconds <= 0 (0: no default)
+	MaxTTLSeconds     int    // Upper bound for TTLSeconds (0: no limit)
+}
+
+// resolveTTL applies the policy's default and maximum TTL to the requested TTL.
+func (p LockPolicy) resolveTTL(ttlSeconds int) int {
+	if ttlSeconds <= 0 && p.DefaultTTLSeconds > 0 {
+		ttlSeconds = p.DefaultTTLSeconds
+	}
+	if p.MaxTTLSeconds > 0 && ttlSeconds > p.MaxTTLSeconds {
+		ttlSeconds = p.MaxTTLSeconds
+	}
+
+	return ttlSeconds
+}
+
+// resolveMaxSharedLocks applies the policy's permit limit, if it sets one, to the given limit.
+func (p LockPolicy) resolveMaxSharedLocks(maxSharedLocks int) int {
+	if p.MaxSharedLocks != 0 {
+		return p.MaxSharedLocks
+	}
+
+	return maxSharedLocks
+}
+
+// validateLockPolicy rejects policies whose limits contradict each other.
+func validateLockPolicy(params SetLockPolicyParams) error {
+	if params.MaxSharedLocks < -1 {
+		return fmt.Errorf("max shared locks (%d) must be -1, 0 or positive", params.MaxSharedLocks)
+	}
+	if params.MaxTTLSeconds > 0 && params.DefaultTTLSeconds > params.MaxTTLSeconds {
+		return fmt.Errorf("default ttl (%ds) exceeds max ttl (%ds)", params.DefaultTTLSeconds, params.MaxTTLSeconds)
+	}
+
+	return nil
+}
+
+type SetLockPolicyParams struct {
+	Name              string // Lock name (or name prefix when Prefix is true)
+	Prefix            bool   // Whether Name is matched as a prefix of lock names
+	MaxSharedLocks    int    // Maximum number of shared permits allowed (-1 for unlimited, 0: the caller's MaxSharedLocks applies)
+	DefaultTTLSeconds int    // TTL used when the caller passes TTLSeconds <= 0 (0: no default)
+	MaxTTLSeconds     int    // Upper bound for TTLSeconds (0: no limit)
+}
+
+type SetLockPolicyResult struct {
+	Policy LockPolicy // The stored policy
+}
+
+// SetLockPolicy creates or replaces the policy for a lock name or prefix.
+func (c *lockClient) SetLockPolicy(ctx context.Context, params SetLockPolicyParams) (SetLockPolicyResult, error) {
+	if err := validateLockPolicy(params); err != nil {
+		return SetLockPolicyResult{}, err
+	}
+
+	tableName := c.options.LockPolicyTableName
+
+	upsertQuery := fmt.Sprintf(`
+		INSERT INTO %s (name, is_prefix, max_shared_locks, default_ttl_seconds, max_ttl_seconds)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name, is_prefix) DO UPDATE
+		SET max_shared_locks = EXCLUDED.max_shared_locks,
+			default_ttl_seconds = EXCLUDED.default_ttl_seconds,
+			max_ttl_seconds = EXCLUDED.max_ttl_seconds;
+	`, tableName)
+
+	_, err := c.db.ExecContext(
+		ctx, upsertQuery,
+		params.Name, params.Prefix, params.MaxSharedLocks, params.DefaultTTLSeconds, params.MaxTTLSeconds,
+	)
+	if err != nil {
+		return SetLockPolicyResult{}, err
+	}
+
+	return SetLockPolicyResult{Policy: LockPolicy(params)}, nil
+}
+
+type GetLockPolicyParams struct {
+	Name string // Lock Name: the policy effective for this name is returned
+}
+
+type GetLockPolicyResult struct {
+	Policy LockPolicy // Effective policy (exact name match first, then the longest matching prefix)
+	Found  bool       // Whether any policy applies to the name
+}
+
+// GetLockPolicy returns the policy that acquire calls apply to the given lock name.
+func (c *lockClient) GetLockPolicy(ctx context.Context, params GetLockPolicyParams) (GetLockPolicyResult, error) {
+	policy, found, err := c.findLockPolicy(ctx, c.db, params.Name)
+	if err != nil {
+		return GetLockPolicyResult{}, err
+	}
+
+	return GetLockPolicyResult{Policy: policy, Found: found}, nil
+}
+
+type DeleteLockPolicyParams struct {
+	Name   string // Lock name (or name prefix when Prefix is true)
+	Prefix bool   // Whether the policy to delete is a prefix policy
+}
+
+type DeleteLockPolicyResult struct {
+	Deleted bool // Whether a policy was deleted
+}
+
+// DeleteLockPolicy removes the policy for a lock name or prefix.
+func (c *lockClient) DeleteLockPolicy(ctx context.Context, params DeleteLockPolicyParams) (DeleteLockPolicyResult, error) {
+	tableName := c.options.LockPolicyTableName
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE name = $1 AND is_prefix = $2;
+	`, tableName)
+
+	result, err := c.db.ExecContext(ctx, deleteQuery, params.Name, params.Prefix)
+	if err != nil {
+		return DeleteLockPolicyResult{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return DeleteLockPolicyResult{}, err
+	}
+
+	return DeleteLockPolicyResult{Deleted: rowsAffected > 0}, nil
+}
+
+// rowQueryer is implemented by both *sql.DB and *sql.Tx.
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// findLockPolicy looks up the effective policy for a lock name.
+func (c *lockClient) findLockPolicy(ctx context.Context, queryer rowQueryer, name string) (LockPolicy, bool, error) {
+	tableName := c.options.LockPolicyTableName
+
+	// 정확히 일치하는 정책 우선, 그 다음 가장 긴 prefix 정책
+	selectQuery := fmt.Sprintf(`
+		SELECT name, is_prefix, max_shared_locks, default_ttl_seconds, max_ttl_seconds
+		FROM %s
+		WHERE (is_prefix = FALSE AND name = $1)
+			OR (is_prefix = TRUE AND starts_with($1, name))
+		ORDER BY is_prefix ASC, length(name) DESC
+		LIMIT 1;
+	`, tableName)
+
+	var policy LockPolicy
+	err := queryer.QueryRowContext(ctx, selectQuery, name).Scan(
+		&policy.Name, &policy.Prefix, &policy.MaxSharedLocks, &policy.DefaultTTLSeconds, &policy.MaxTTLSeconds,
+	)
+	if err == sql.ErrNoRows {
+		return LockPolicy{}, false, nil
+	}
+	if err != nil {
+		return LockPolicy{}, false, err
+	}
+
+	return policy, true, nil
+}
@@ -0,0 +1,276 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/myyrakle/pglock"
+)
+
+type ClientOptions struct {
+	BaseURL    string       // [required] example: "http://pglock-server:8080"
+	Prefix     string       // [optional] path prefix of the API. default: "/v1"
+	Token      string       // [optional] bearer token sent in the Authorization header
+	HTTPClient *http.Client // [optional] default: a client without timeout, since XLock and SLock wait on the server
+}
+
+func (options *ClientOptions) SetDefaults() {
+	options.BaseURL = strings.TrimSuffix(options.BaseURL, "/")
+
+	if options.Prefix == "" {
+		options.Prefix = DefaultPrefix
+	}
+	options.Prefix = strings.TrimSuffix(options.Prefix, "/")
+
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{}
+	}
+}
+
+// NewLockClient returns a pglock.LockClient that forwards every call to a server created with NewHandler.
+func NewLockClient(options ClientOptions) pglock.LockClient {
+	options.SetDefaults()
+
+	return &lockClient{
+		options: options,
+	}
+}
+
+type lockClient struct {
+	options ClientOptions
+}
+
+var (
+	_ pglock.LockClient      = (*lockClient)(nil)
+	_ pglock.BarrierStore    = (*lockClient)(nil)
+	_ pglock.LatchStore      = (*lockClient)(nil)
+	_ pglock.TaskResultStore = (*lockClient)(nil)
+	_ pglock.JobStatusStore  = (*lockClient)(nil)
+)
+
+// Initialize checks that the server is reachable. The server sets up its own tables at startup;
+// SetupTables is an administrative method that needs the admin token.
+func (c *lockClient) Initialize() error {
+	return c.Connect()
+}
+
+// Connect checks that the server is reachable.
+func (c *lockClient) Connect() error {
+	_, err := call[struct{}, struct{}](context.Background(), c, PathHealth, struct{}{})
+
+	return err
+}
+
+func (c *lockClient) SetupTables() error {
+	_, err := call[struct{}, struct{}](context.Background(), c, PathSetupTables, struct{}{})
+
+	return err
+}
+
+// TryXLock and the other acquisitions record this process as the owner, rather than the server process.
+func (c *lockClient) TryXLock(ctx context.Context, params pglock.TryXLockParams) (pglock.TryXLockResult, error) {
+	params.Owner = pglock.LocalOwner(params.Owner)
+
+	return call[pglock.TryXLockParams, pglock.TryXLockResult](ctx, c, PathTryXLock, params)
+}
+
+func (c *lockClient) XLock(ctx context.Context, params pglock.XLockParams) (pglock.XLockResult, error) {
+	params.Owner = pglock.LocalOwner(params.Owner)
+
+	return call[pglock.XLockParams, pglock.XLockResult](ctx, c, PathXLock, params)
+}
+
+func (c *lockClient) TrySLock(ctx context.Context, params pglock.TrySLockParams) (pglock.TrySLockResult, error) {
+	params.Owner = pglock.LocalOwner(params.Owner)
+
+	return call[pglock.TrySLockParams, pglock.TrySLockResult](ctx, c, PathTrySLock, params)
+}
+
+func (c *lockClient) SLock(ctx context.Context, params pglock.SLockParams) (pglock.SLockResult, error) {
+	params.Owner = pglock.LocalOwner(params.Owner)
+
+	return call[pglock.SLockParams, pglock.SLockResult](ctx, c, PathSLock, params)
+}
+
+// TryXLockTx returns pglock.ErrNotSupported: the caller's transaction cannot be sent to the server.
+func (c *lockClient) TryXLockTx(ctx context.Context, tx *sql.Tx, params pglock.TryXLockTxParams) (pglock.TryXLockTxResult, error) {
+	return pglock.TryXLockTxResult{}, pglock.ErrNotSupported
+}
+
+// XLockTx returns pglock.ErrNotSupported: the caller's transaction cannot be sent to the server.
+func (c *lockClient) XLockTx(ctx context.Context, tx *sql.Tx, params pglock.XLockTxParams) (pglock.XLockTxResult, error) {
+	return pglock.XLockTxResult{}, pglock.ErrNotSupported
+}
+
+// TrySLockTx returns pglock.ErrNotSupported: the caller's transaction cannot be sent to the server.
+func (c *lockClient) TrySLockTx(ctx context.Context, tx *sql.Tx, params pglock.TrySLockTxParams) (pglock.TrySLockTxResult, error) {
+	return pglock.TrySLockTxResult{}, pglock.ErrNotSupported
+}
+
+func (c *lockClient) Unlock(ctx context.Context, params pglock.UnlockParams) (pglock.UnlockResult, error) {
+	return call[pglock.UnlockParams, pglock.UnlockResult](ctx, c, PathUnlock, params)
+}
+
+func (c *lockClient) Refresh(ctx context.Context, params pglock.RefreshParams) (pglock.RefreshResult, error) {
+	return call[pglock.RefreshParams, pglock.RefreshResult](ctx, c, PathRefresh, params)
+}
+
+func (c *lockClient) GetValue(ctx context.Context, params pglock.GetValueParams) (pglock.GetValueResult, error) {
+	return call[pglock.GetValueParams, pglock.GetValueResult](ctx, c, PathGetValue, params)
+}
+
+func (c *lockClient) SetValue(ctx context.Context, params pglock.SetValueParams) (pglock.SetValueResult, error) {
+	return call[pglock.SetValueParams, pglock.SetValueResult](ctx, c, PathSetValue, params)
+}
+
+func (c *lockClient) TryHierarchyLock(ctx context.Context, params pglock.TryHierarchyLockParams) (pglock.TryHierarchyLockResult, error) {
+	return call[pglock.TryHierarchyLockParams, pglock.TryHierarchyLockResult](ctx, c, PathTryHierarchyLock, params)
+}
+
+func (c *lockClient) HierarchyLock(ctx context.Context, params pglock.HierarchyLockParams) (pglock.HierarchyLockResult, error) {
+	return call[pglock.HierarchyLockParams, pglock.HierarchyLockResult](ctx, c, PathHierarchyLock, params)
+}
+
+func (c *lockClient) HierarchyUnlock(ctx context.Context, params pglock.HierarchyUnlockParams) (pglock.HierarchyUnlockResult, error) {
+	return call[pglock.HierarchyUnlockParams, pglock.HierarchyUnlockResult](ctx, c, PathHierarchyUnlock, params)
+}
+
+func (c *lockClient) ArriveBarrier(ctx context.Context, params pglock.ArriveBarrierParams) (pglock.ArriveBarrierResult, error) {
+	return call[pglock.ArriveBarrierParams, pglock.ArriveBarrierResult](ctx, c, PathArriveBarrier, params)
+}
+
+func (c *lockClient) LeaveBarrier(ctx context.Context, params pglock.LeaveBarrierParams) (pglock.LeaveBarrierResult, error) {
+	return call[pglock.LeaveBarrierParams, pglock.LeaveBarrierResult](ctx, c, PathLeaveBarrier, params)
+}
+
+func (c *lockClient) CreateLatch(ctx context.Context, params pglock.CreateLatchParams) (pglock.CreateLatchResult, error) {
+	return call[pglock.CreateLatchParams, pglock.CreateLatchResult](ctx, c, PathCreateLatch, params)
+}
+
+func (c *lockClient) CountDown(ctx context.Context, params pglock.CountDownParams) (pglock.CountDownResult, error) {
+	return call[pglock.CountDownParams, pglock.CountDownResult](ctx, c, PathCountDown, params)
+}
+
+func (c *lockClient) DescribeLatch(ctx context.Context, params pglock.DescribeLatchParams) (pglock.DescribeLatchResult, error) {
+	return call[pglock.DescribeLatchParams, pglock.DescribeLatchResult](ctx, c, PathDescribeLatch, params)
+}
+
+func (c *lockClient) StoreTaskResult(ctx context.Context, params pglock.StoreTaskResultParams) (pglock.StoreTaskResultResult, error) {
+	return call[pglock.StoreTaskResultParams, pglock.StoreTaskResultResult](ctx, c, PathStoreTaskResult, params)
+}
+
+func (c *lockClient) GetTaskResult(ctx context.Context, params pglock.GetTaskResultParams) (pglock.GetTaskResultResult, error) {
+	return call[pglock.GetTaskResultParams, pglock.GetTaskResultResult](ctx, c, PathGetTaskResult, params)
+}
+
+func (c *lockClient) PruneTaskResults(ctx context.Context, params pglock.PruneTaskResultsParams) (pglock.PruneTaskResultsResult, error) {
+	return call[pglock.PruneTaskResultsParams, pglock.PruneTaskResultsResult](ctx, c, PathPruneTaskResults, params)
+}
+
+func (c *lockClient) DeleteTaskResult(ctx context.Context, params pglock.DeleteTaskResultParams) (pglock.DeleteTaskResultResult, error) {
+	return call[pglock.DeleteTaskResultParams, pglock.DeleteTaskResultResult](ctx, c, PathDeleteTaskResult, params)
+}
+
+func (c *lockClient) SaveJobStatus(ctx context.Context, params pglock.SaveJobStatusParams) (pglock.SaveJobStatusResult, error) {
+	return call[pglock.SaveJobStatusParams, pglock.SaveJobStatusResult](ctx, c, PathSaveJobStatus, params)
+}
+
+func (c *lockClient) GetJobStatus(ctx context.Context, params pglock.GetJobStatusParams) (pglock.GetJobStatusResult, error) {
+	return call[pglock.GetJobStatusParams, pglock.GetJobStatusResult](ctx, c, PathGetJobStatus, params)
+}
+
+func (c *lockClient) OpenSession(ctx context.Context, params pglock.OpenSessionParams) (pglock.OpenSessionResult, error) {
+	return call[pglock.OpenSessionParams, pglock.OpenSessionResult](ctx, c, PathOpenSession, params)
+}
+
+func (c *lockClient) RefreshSession(ctx context.Context, params pglock.RefreshSessionParams) (pglock.RefreshSessionResult, error) {
+	return call[pglock.RefreshSessionParams, pglock.RefreshSessionResult](ctx, c, PathRefreshSession, params)
+}
+
+func (c *lockClient) CloseSession(ctx context.Context, params pglock.CloseSessionParams) (pglock.CloseSessionResult, error) {
+	return call[pglock.CloseSessionParams, pglock.CloseSessionResult](ctx, c, PathCloseSession, params)
+}
+
+func (c *lockClient) ForceUnlock(ctx context.Context, params pglock.ForceUnlockParams) (pglock.ForceUnlockResult, error) {
+	return call[pglock.ForceUnlockParams, pglock.ForceUnlockResult](ctx, c, PathForceUnlock, params)
+}
+
+func (c *lockClient) DescribeLock(ctx context.Context, params pglock.DescribeLockParams) (pglock.DescribeLockResult, error) {
+	return call[pglock.DescribeLockParams, pglock.DescribeLockResult](ctx, c, PathDescribeLock, params)
+}
+
+func (c *lockClient) ListLocks(ctx context.Context, params pglock.ListLocksParams) (pglock.ListLocksResult, error) {
+	return call[pglock.ListLocksParams, pglock.ListLocksResult](ctx, c, PathListLocks, params)
+}
+
+func (c *lockClient) ListNamespaces(ctx context.Context, params pglock.ListNamespacesParams) (pglock.ListNamespacesResult, error) {
+	return call[pglock.ListNamespacesParams, pglock.ListNamespacesResult](ctx, c, PathListNamespaces, params)
+}
+
+func (c *lockClient) SetLockPolicy(ctx context.Context, params pglock.SetLockPolicyParams) (pglock.SetLockPolicyResult, error) {
+	return call[pglock.SetLockPolicyParams, pglock.SetLockPolicyResult](ctx, c, PathSetLockPolicy, params)
+}
+
+func (c *lockClient) GetLockPolicy(ctx context.Context, params pglock.GetLockPolicyParams) (pglock.GetLockPolicyResult, error) {
+	return call[pglock.GetLockPolicyParams, pglock.GetLockPolicyResult](ctx, c, PathGetLockPolicy, params)
+}
+
+func (c *lockClient) DeleteLockPolicy(ctx context.Context, params pglock.DeleteLockPolicyParams) (pglock.DeleteLockPolicyResult, error) {
+	return call[pglock.DeleteLockPolicyParams, pglock.DeleteLockPolicyResult](ctx, c, PathDeleteLockPolicy, params)
+}
+
+func (c *lockClient) GetLockHistory(ctx context.Context, params pglock.GetLockHistoryParams) (pglock.GetLockHistoryResult, error) {
+	return call[pglock.GetLockHistoryParams, pglock.GetLockHistoryResult](ctx, c, PathGetLockHistory, params)
+}
+
+func (c *lockClient) PruneLockHistory(ctx context.Context, params pglock.PruneLockHistoryParams) (pglock.PruneLockHistoryResult, error) {
+	return call[pglock.PruneLockHistoryParams, pglock.PruneLockHistoryResult](ctx, c, PathPruneLockHistory, params)
+}
+
+// call posts params to the endpoint at path and decodes the result.
+func call[P any, R any](ctx context.Context, c *lockClient, path string, params P) (R, error) {
+	var result R
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.options.BaseURL+c.options.Prefix+path, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.options.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.options.Token)
+	}
+
+	response, err := c.options.HTTPClient.Do(request)
+	if err != nil {
+		// 컨텍스트 취소는 로컬 클라이언트와 같은 에러로 반환
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		return result, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(response.Body).Decode(&errorResponse); err != nil {
+			return result, &Error{StatusCode: response.StatusCode, Code: CodeInternal, Message: response.Status}
+		}
+		return result, errorFromResponse(response.StatusCode, errorResponse)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,138 @@
+// Package remote exposes a pglock.LockClient over HTTP/JSON and provides a
+// pglock.LockClient implementation that talks to such a server.
+//
+// Every LockClient method is served as POST {prefix}/{method} where the request body is the
+// JSON encoding of the method's params struct and the response body is the JSON encoding of
+// its result struct. Failures are returned as an ErrorResponse with a non-2xx status.
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/myyrakle/pglock"
+)
+
+// Paths of the endpoints, relative to the API prefix
+const (
+	PathHealth           = "/health"
+	PathSetupTables      = "/setup-tables"
+	PathTryXLock         = "/try-xlock"
+	PathXLock            = "/xlock"
+	PathTrySLock         = "/try-slock"
+	PathSLock            = "/slock"
+	PathUnlock           = "/unlock"
+	PathRefresh          = "/refresh"
+	PathGetValue         = "/get-value"
+	PathSetValue         = "/set-value"
+	PathTryHierarchyLock = "/try-hierarchy-lock"
+	PathHierarchyLock    = "/hierarchy-lock"
+	PathHierarchyUnlock  = "/hierarchy-unlock"
+	PathArriveBarrier    = "/arrive-barrier"
+	PathLeaveBarrier     = "/leave-barrier"
+	PathCreateLatch      = "/create-latch"
+	PathCountDown        = "/count-down"
+	PathDescribeLatch    = "/describe-latch"
+	PathStoreTaskResult  = "/store-task-result"
+	PathGetTaskResult    = "/get-task-result"
+	PathDeleteTaskResult = "/delete-task-result"
+	PathPruneTaskResults = "/prune-task-results"
+	PathSaveJobStatus    = "/save-job-status"
+	PathGetJobStatus     = "/get-job-status"
+	PathOpenSession      = "/open-session"
+	PathRefreshSession   = "/refresh-session"
+	PathCloseSession     = "/close-session"
+	PathForceUnlock      = "/force-unlock"
+	PathDescribeLock     = "/describe-lock"
+	PathListLocks        = "/list-locks"
+	PathListNamespaces   = "/list-namespaces"
+	PathSetLockPolicy    = "/set-lock-policy"
+	PathGetLockPolicy    = "/get-lock-policy"
+	PathDeleteLockPolicy = "/delete-lock-policy"
+	PathGetLockHistory   = "/get-lock-history"
+	PathPruneLockHistory = "/prune-lock-history"
+)
+
+// AdminPaths are the methods that change tables or override other holders, served only with HandlerOptions.AdminToken
+var AdminPaths = []string{
+	PathSetupTables,
+	PathForceUnlock,
+	PathSetLockPolicy,
+	PathDeleteLockPolicy,
+	PathPruneLockHistory,
+}
+
+// DefaultPrefix is the default path prefix of the API
+const DefaultPrefix = "/v1"
+
+// Error codes carried in ErrorResponse
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeCanceled         = "canceled"
+	CodeDeadlineExceeded = "deadline_exceeded"
+	CodeLockLost         = "lock_lost"
+	CodeSessionLost      = "session_lost"
+	CodeTaskFailed       = "task_failed"
+	CodeNotLockHolder    = "not_lock_holder"
+	CodeAuditDisabled    = "audit_disabled"
+	CodeNotSupported     = "not_supported"
+	CodeInternal         = "internal"
+)
+
+// ErrorResponse is the body of every failed request
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is returned by the remote client when the server reports an error without a pglock equivalent.
+type Error struct {
+	StatusCode int    // HTTP status code of the response
+	Code       string // Error code from the response body
+	Message    string // Error message from the response body
+}
+
+func (e *Error) Error() string {
+	return "pglock/remote: " + e.Code + ": " + e.Message
+}
+
+// codedErrors maps error codes to the errors they stand for, in both directions
+var codedErrors = []struct {
+	code   string
+	err    error
+	status int
+}{
+	{code: CodeCanceled, err: context.Canceled, status: 499},
+	{code: CodeDeadlineExceeded, err: context.DeadlineExceeded, status: http.StatusGatewayTimeout},
+	{code: CodeLockLost, err: pglock.ErrLockLost, status: http.StatusConflict},
+	{code: CodeSessionLost, err: pglock.ErrSessionLost, status: http.StatusConflict},
+	{code: CodeTaskFailed, err: pglock.ErrTaskFailed, status: http.StatusConflict},
+	{code: CodeNotLockHolder, err: pglock.ErrNotLockHolder, status: http.StatusConflict},
+	{code: CodeAuditDisabled, err: pglock.ErrAuditDisabled, status: http.StatusNotImplemented},
+	{code: CodeNotSupported, err: pglock.ErrNotSupported, status: http.StatusNotImplemented},
+}
+
+// errorCodeOf returns the code and HTTP status reported for err.
+func errorCodeOf(err error) (string, int) {
+	for _, coded := range codedErrors {
+		if errors.Is(err, coded.err) {
+			return coded.code, coded.status
+		}
+	}
+
+	return CodeInternal, http.StatusInternalServerError
+}
+
+// errorFromResponse converts an ErrorResponse back into the error the server-side client returned.
+func errorFromResponse(statusCode int, response ErrorResponse) error {
+	for _, coded := range codedErrors {
+		if coded.code == response.Code {
+			return coded.err
+		}
+	}
+
+	return &Error{StatusCode: statusCode, Code: response.Code, Message: response.Message}
+}
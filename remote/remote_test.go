@@ -0,0 +1,153 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/myyrakle/pglock"
+)
+
+// stubLockClient serves TryXLock, XLock and GetLockHistory; other methods are not used by the tests
+type stubLockClient struct {
+	pglock.LockClient
+
+	holder    string
+	expiresAt time.Time
+}
+
+func (c *stubLockClient) TryXLock(ctx context.Context, params pglock.TryXLockParams) (pglock.TryXLockResult, error) {
+	if c.holder != "" && c.holder != params.LockID {
+		return pglock.TryXLockResult{Acquired: false}, nil
+	}
+
+	c.holder = params.LockID
+	return pglock.TryXLockResult{ExpiresAt: c.expiresAt, Acquired: true}, nil
+}
+
+func (c *stubLockClient) XLock(ctx context.Context, params pglock.XLockParams) (pglock.XLockResult, error) {
+	// 항상 다른 보유자가 있는 것처럼 컨텍스트 종료까지 대기
+	<-ctx.Done()
+	return pglock.XLockResult{}, ctx.Err()
+}
+
+func (c *stubLockClient) GetLockHistory(ctx context.Context, params pglock.GetLockHistoryParams) (pglock.GetLockHistoryResult, error) {
+	return pglock.GetLockHistoryResult{}, pglock.ErrAuditDisabled
+}
+
+func newTestClient(t *testing.T, backend pglock.LockClient, token string) pglock.LockClient {
+	server := httptest.NewServer(NewHandler(backend, HandlerOptions{Token: "secret"}))
+	t.Cleanup(server.Close)
+
+	return NewLockClient(ClientOptions{
+		BaseURL: server.URL,
+		Token:   token,
+	})
+}
+
+// TestRemote_RoundTrip tests that params, results and errors are carried over HTTP
+func TestRemote_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Millisecond)
+	client := newTestClient(t, &stubLockClient{expiresAt: expiresAt}, "secret")
+
+	require.NoError(t, client.Connect())
+
+	// 1. 획득 결과 전달
+	result1, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: "test_remote", LockID: "lock_1", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.True(t, result1.Acquired)
+	assert.True(t, expiresAt.Equal(result1.ExpiresAt))
+
+	result2, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: "test_remote", LockID: "lock_2", TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, result2.Acquired)
+
+	// 2. 대기 중 컨텍스트 만료는 같은 에러로 반환
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = client.XLock(waitCtx, pglock.XLockParams{Name: "test_remote", LockID: "lock_2", TTLSeconds: 60})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// 3. sentinel 에러 전달
+	_, err = client.GetLockHistory(ctx, pglock.GetLockHistoryParams{Name: "test_remote"})
+	assert.ErrorIs(t, err, pglock.ErrAuditDisabled)
+}
+
+// TestRemote_RequiresToken tests that requests without the bearer token are rejected
+func TestRemote_RequiresToken(t *testing.T) {
+	client := newTestClient(t, &stubLockClient{}, "wrong")
+
+	err := client.Connect()
+
+	var remoteErr *Error
+	require.ErrorAs(t, err, &remoteErr)
+	assert.Equal(t, CodeUnauthorized, remoteErr.Code)
+}
+
+// TestRemote_AdminToken tests that administrative methods are refused without the admin token
+func TestRemote_AdminToken(t *testing.T) {
+	ctx := context.Background()
+	backend := pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})
+	forceUnlock := func(client pglock.LockClient) error {
+		_, err := client.ForceUnlock(ctx, pglock.ForceUnlockParams{Name: "test_remote_admin"})
+		return err
+	}
+
+	// 1. 관리자 토큰이 없는 서버는 관리 메서드를 거부
+	client := newTestClient(t, backend, "secret")
+	var remoteErr *Error
+	require.ErrorAs(t, forceUnlock(client), &remoteErr)
+	assert.Equal(t, CodeForbidden, remoteErr.Code)
+
+	// 2. 관리자 토큰이 있는 서버는 일반 토큰을 거부
+	server := httptest.NewServer(NewHandler(backend, HandlerOptions{Token: "secret", AdminToken: "admin"}))
+	t.Cleanup(server.Close)
+
+	client = NewLockClient(ClientOptions{BaseURL: server.URL, Token: "secret"})
+	require.ErrorAs(t, forceUnlock(client), &remoteErr)
+	assert.Equal(t, CodeUnauthorized, remoteErr.Code)
+
+	// 3. 관리자 토큰은 관리 메서드와 일반 메서드 모두 허용
+	adminClient := NewLockClient(ClientOptions{BaseURL: server.URL, Token: "admin"})
+	assert.NoError(t, forceUnlock(adminClient))
+	assert.NoError(t, adminClient.Connect())
+}
+
+// TestRemote_InitializeWithoutAdminToken tests that clients with the regular token can initialize
+func TestRemote_InitializeWithoutAdminToken(t *testing.T) {
+	backend := pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})
+	server := httptest.NewServer(NewHandler(backend, HandlerOptions{Token: "secret", AdminToken: "admin"}))
+	t.Cleanup(server.Close)
+
+	client := NewLockClient(ClientOptions{BaseURL: server.URL, Token: "secret"})
+	require.NoError(t, client.Initialize())
+
+	// 테이블 설정은 여전히 관리자 토큰이 필요
+	var remoteErr *Error
+	require.ErrorAs(t, client.SetupTables(), &remoteErr)
+	assert.Equal(t, CodeUnauthorized, remoteErr.Code)
+}
+
+// TestRemote_MaxRequestBytes tests that request bodies over the limit are refused
+func TestRemote_MaxRequestBytes(t *testing.T) {
+	ctx := context.Background()
+	backend := pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})
+	server := httptest.NewServer(NewHandler(backend, HandlerOptions{Token: "secret", MaxRequestBytes: 1024}))
+	t.Cleanup(server.Close)
+
+	client := NewLockClient(ClientOptions{BaseURL: server.URL, Token: "secret"})
+	result, err := client.TryXLock(ctx, pglock.TryXLockParams{Name: "test_remote_max_bytes", LockID: "lock_1", TTLSeconds: 60})
+	require.NoError(t, err)
+	require.True(t, result.Acquired)
+
+	_, err = client.SetValue(ctx, pglock.SetValueParams{Name: "test_remote_max_bytes", LockID: "lock_1", Value: make([]byte, 2048)})
+	var remoteErr *Error
+	require.ErrorAs(t, err, &remoteErr)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, remoteErr.StatusCode)
+}
@@ -0,0 +1,193 @@
+package remote
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/myyrakle/pglock"
+)
+
+type HandlerOptions struct {
+	Prefix     string       // [optional] path prefix of the API. default: "/v1"
+	Token      string       // [optional] bearer token required in the Authorization header (AdminToken is accepted too). default: no authentication
+	AdminToken string       // [optional] bearer token required by the administrative methods (AdminPaths). default: administrative methods are refused
+	Logger     *slog.Logger // [optional] logs failed requests. default: discarded
+
+	MaxRequestBytes int64 // [optional] maximum size of a request body. default: DefaultMaxRequestBytes
+}
+
+// DefaultMaxRequestBytes is the default limit on the size of a request body
+const DefaultMaxRequestBytes = 1 << 20
+
+func (options *HandlerOptions) SetDefaults() {
+	if options.Prefix == "" {
+		options.Prefix = DefaultPrefix
+	}
+	options.Prefix = strings.TrimSuffix(options.Prefix, "/")
+
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
+
+	if options.MaxRequestBytes <= 0 {
+		options.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+}
+
+// NewHandler serves the methods of client over HTTP/JSON.
+// Blocking methods (XLock, SLock) wait while the request is open and stop when the caller goes away.
+// The administrative methods (AdminPaths) are only served with AdminToken.
+func NewHandler(client pglock.LockClient, options HandlerOptions) http.Handler {
+	options.SetDefaults()
+
+	mux := http.NewServeMux()
+	route := func(path string, handler http.Handler) {
+		if slices.Contains(AdminPaths, path) {
+			handler = requireAdminToken(options.AdminToken, handler)
+		} else if options.Token != "" {
+			handler = requireToken(handler, options.Token, options.AdminToken)
+		}
+		mux.Handle("POST "+options.Prefix+path, handler)
+	}
+
+	route(PathHealth, handle(options, func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, nil
+	}))
+	route(PathSetupTables, handle(options, func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, client.SetupTables()
+	}))
+	route(PathTryXLock, handle(options, client.TryXLock))
+	route(PathXLock, handle(options, client.XLock))
+	route(PathTrySLock, handle(options, client.TrySLock))
+	route(PathSLock, handle(options, client.SLock))
+	route(PathUnlock, handle(options, client.Unlock))
+	route(PathRefresh, handle(options, client.Refresh))
+	route(PathGetValue, handle(options, client.GetValue))
+	route(PathSetValue, handle(options, client.SetValue))
+	route(PathTryHierarchyLock, handle(options, client.TryHierarchyLock))
+	route(PathHierarchyLock, handle(options, client.HierarchyLock))
+	route(PathHierarchyUnlock, handle(options, client.HierarchyUnlock))
+	route(PathArriveBarrier, handle(options, storeMethod(client, pglock.BarrierStore.ArriveBarrier)))
+	route(PathLeaveBarrier, handle(options, storeMethod(client, pglock.BarrierStore.LeaveBarrier)))
+	route(PathCreateLatch, handle(options, storeMethod(client, pglock.LatchStore.CreateLatch)))
+	route(PathCountDown, handle(options, storeMethod(client, pglock.LatchStore.CountDown)))
+	route(PathDescribeLatch, handle(options, storeMethod(client, pglock.LatchStore.DescribeLatch)))
+	route(PathStoreTaskResult, handle(options, storeMethod(client, pglock.TaskResultStore.StoreTaskResult)))
+	route(PathGetTaskResult, handle(options, storeMethod(client, pglock.TaskResultStore.GetTaskResult)))
+	route(PathDeleteTaskResult, handle(options, storeMethod(client, pglock.TaskResultStore.DeleteTaskResult)))
+	route(PathPruneTaskResults, handle(options, storeMethod(client, pglock.TaskResultStore.PruneTaskResults)))
+	route(PathSaveJobStatus, handle(options, storeMethod(client, pglock.JobStatusStore.SaveJobStatus)))
+	route(PathGetJobStatus, handle(options, storeMethod(client, pglock.JobStatusStore.GetJobStatus)))
+	route(PathOpenSession, handle(options, client.OpenSession))
+	route(PathRefreshSession, handle(options, client.RefreshSession))
+	route(PathCloseSession, handle(options, client.CloseSession))
+	route(PathForceUnlock, handle(options, client.ForceUnlock))
+	route(PathDescribeLock, handle(options, client.DescribeLock))
+	route(PathListLocks, handle(options, client.ListLocks))
+	route(PathListNamespaces, handle(options, client.ListNamespaces))
+	route(PathSetLockPolicy, handle(options, client.SetLockPolicy))
+	route(PathGetLockPolicy, handle(options, client.GetLockPolicy))
+	route(PathDeleteLockPolicy, handle(options, client.DeleteLockPolicy))
+	route(PathGetLockHistory, handle(options, client.GetLockHistory))
+	route(PathPruneLockHistory, handle(options, client.PruneLockHistory))
+
+	return mux
+}
+
+// handle adapts a LockClient method to an HTTP handler.
+func handle[P any, R any](options HandlerOptions, method func(ctx context.Context, params P) (R, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 본문이 비어 있으면 params의 zero value 사용 (크기 제한을 넘는 본문은 읽지 않음)
+		var params P
+		body := http.MaxBytesReader(w, r.Body, options.MaxRequestBytes)
+		if err := json.NewDecoder(body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+			status := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeError(w, status, ErrorResponse{Code: CodeBadRequest, Message: err.Error()})
+			return
+		}
+
+		result, err := method(r.Context(), params)
+		if err != nil {
+			code, status := errorCodeOf(err)
+			if code == CodeInternal {
+				options.Logger.ErrorContext(r.Context(), "pglock/remote: request failed",
+					slog.String("path", r.URL.Path), slog.Any("error", err))
+			}
+			writeError(w, status, ErrorResponse{Code: code, Message: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// storeMethod adapts a method of a store interface (e.g. pglock.BarrierStore) that client may not implement.
+func storeMethod[S any, P any, R any](client pglock.LockClient, method func(store S, ctx context.Context, params P) (R, error)) func(ctx context.Context, params P) (R, error) {
+	return func(ctx context.Context, params P) (R, error) {
+		store, ok := client.(S)
+		if !ok {
+			var result R
+			return result, pglock.ErrNotSupported
+		}
+
+		return method(store, ctx, params)
+	}
+}
+
+// requireToken rejects requests that do not present one of the non-empty tokens.
+func requireToken(next http.Handler, tokens ...string) http.Handler {
+	expected := [][]byte{}
+	for _, token := range tokens {
+		if token != "" {
+			expected = append(expected, []byte("Bearer "+token))
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual := []byte(r.Header.Get("Authorization"))
+
+		authorized := 0
+		for _, token := range expected {
+			// 어느 토큰과 일치하는지 시간으로 드러나지 않도록 모두 비교
+			authorized |= subtle.ConstantTimeCompare(actual, token)
+		}
+		if authorized != 1 {
+			writeError(w, http.StatusUnauthorized, ErrorResponse{Code: CodeUnauthorized, Message: "invalid or missing bearer token"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdminToken guards an administrative method, which is refused entirely without an admin token.
+func requireAdminToken(adminToken string, next http.Handler) http.Handler {
+	if adminToken == "" {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusForbidden, ErrorResponse{Code: CodeForbidden, Message: "administrative methods are disabled on this server"})
+		})
+	}
+
+	return requireToken(next, adminToken)
+}
+
+func writeError(w http.ResponseWriter, status int, response ErrorResponse) {
+	writeJSON(w, status, response)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
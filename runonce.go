@@ -0,0 +1,366 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RunOnce runs a task at most once per key across all processes: the process that gets the
+// exclusive lock "run-once/<key>" executes it and records the outcome in the task result table
+// under the same name, and every other caller, now or later, receives the recorded outcome instead of running it.
+
+// RunOnceLockPrefix is prepended to the key to form the lock name and the task result key,
+// so that RunOnce keys never meet the keys of Singleflight (SingleflightPrefix)
+const RunOnceLockPrefix = "run-once/"
+
+const (
+	// DefaultRunOnceTTLSeconds is the default lease TTL of the lock held while a task runs
+	DefaultRunOnceTTLSeconds = 30
+	// DefaultRunOnceFailureTTLSeconds is the default time a failed run is recorded before the task can run again
+	DefaultRunOnceFailureTTLSeconds = 60
+)
+
+func (c *lockClient) createTaskResultTable(ctx context.Context) error {
+	tableName := c.options.TaskResultTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			key TEXT PRIMARY KEY,
+			value BYTEA,
+			failed BOOLEAN NOT NULL DEFAULT FALSE,
+			error TEXT NOT NULL DEFAULT '',
+			completed_at TIMESTAMPTZ NOT NULL,
+			expires_at TIMESTAMPTZ
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+	if err != nil {
+		return err
+	}
+
+	createIndexSQL := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_expires_at ON %s (expires_at);
+	`, tableName, tableName)
+
+	_, err = c.db.ExecContext(ctx, createIndexSQL)
+
+	return err
+}
+
+// TaskResult is the recorded outcome of a task
+type TaskResult struct {
+	Key         string    // Task key
+	Value       []byte    // Result of the task (nil if it failed)
+	Failed      bool      // Whether the task returned an error
+	Error       string    // Error message of the task, if it failed
+	CompletedAt time.Time // Time the outcome was recorded
+	ExpiresAt   time.Time // Time the outcome is discarded (zero if it is kept forever)
+}
+
+type StoreTaskResultParams struct {
+	Key        string // Task key
+	Value      []byte // Result of the task
+	Failed     bool   // Whether the task returned an error
+	Error      string // Error message of the task, if it failed
+	TTLSeconds int    // Time-To-Live: the outcome is discarded after this duration (0 to keep it forever)
+}
+
+type StoreTaskResultResult struct {
+	Result TaskResult // The recorded outcome (the earlier one if the key already had a live outcome)
+	Stored bool       // Whether this outcome was recorded
+}
+
+type GetTaskResultParams struct {
+	Key string // Task key
+}
+
+type GetTaskResultResult struct {
+	Result TaskResult // The recorded outcome
+	Found  bool       // Whether the key has a live outcome
+}
+
+type DeleteTaskResultParams struct {
+	Key string // Task key
+}
+
+type DeleteTaskResultResult struct {
+	Deleted bool // Whether the key had an outcome (live or expired)
+}
+
+type PruneTaskResultsParams struct{}
+
+type PruneTaskResultsResult struct {
+	Deleted int64 // Number of deleted outcomes
+}
+
+// taskResultExpiresAt returns the expiration time of an outcome recorded at now, or zero if it never expires.
+func taskResultExpiresAt(now time.Time, ttlSeconds int) time.Time {
+	if ttlSeconds <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(time.Duration(ttlSeconds) * time.Second)
+}
+
+// StoreTaskResult records the outcome of a task, unless the key already has a live one.
+// The first outcome wins until it expires, so a recorded result never changes while it is live.
+func (c *lockClient) StoreTaskResult(ctx context.Context, params StoreTaskResultParams) (StoreTaskResultResult, error) {
+	tableName := c.options.TaskResultTableName
+
+	completedAt := time.Now()
+	expiresAt := taskResultExpiresAt(completedAt, params.TTLSeconds)
+
+	// 만료된 결과만 덮어씀
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s AS task_result (key, value, failed, error, completed_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, failed = EXCLUDED.failed, error = EXCLUDED.error,
+			completed_at = EXCLUDED.completed_at, expires_at = EXCLUDED.expires_at
+		WHERE task_result.expires_at IS NOT NULL AND task_result.expires_at <= EXCLUDED.completed_at;
+	`, tableName)
+	result, err := c.db.ExecContext(
+		ctx, insertQuery,
+		params.Key, params.Value, params.Failed, params.Error, completedAt, sql.NullTime{Time: expiresAt, Valid: !expiresAt.IsZero()},
+	)
+	if err != nil {
+		return StoreTaskResultResult{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return StoreTaskResultResult{}, err
+	}
+	if rowsAffected > 0 {
+		return StoreTaskResultResult{
+			Result: TaskResult{
+				Key:         params.Key,
+				Value:       params.Value,
+				Failed:      params.Failed,
+				Error:       params.Error,
+				CompletedAt: completedAt,
+				ExpiresAt:   expiresAt,
+			},
+			Stored: true,
+		}, nil
+	}
+
+	// 이미 기록된 결과 반환
+	getResult, err := c.GetTaskResult(ctx, GetTaskResultParams{Key: params.Key})
+	if err != nil {
+		return StoreTaskResultResult{}, err
+	}
+
+	return StoreTaskResultResult{Result: getResult.Result, Stored: false}, nil
+}
+
+// GetTaskResult returns the recorded outcome of a task, unless it has expired.
+func (c *lockClient) GetTaskResult(ctx context.Context, params GetTaskResultParams) (GetTaskResultResult, error) {
+	tableName := c.options.TaskResultTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT value, failed, error, completed_at, expires_at
+		FROM %s
+		WHERE key = $1 AND (expires_at IS NULL OR expires_at > $2);
+	`, tableName)
+
+	result := TaskResult{Key: params.Key}
+	var expiresAt sql.NullTime
+	err := c.db.QueryRowContext(ctx, selectQuery, params.Key, time.Now()).Scan(
+		&result.Value, &result.Failed, &result.Error, &result.CompletedAt, &expiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return GetTaskResultResult{Found: false}, nil
+	}
+	if err != nil {
+		return GetTaskResultResult{}, err
+	}
+	if expiresAt.Valid {
+		result.ExpiresAt = expiresAt.Time
+	}
+
+	return GetTaskResultResult{Result: result, Found: true}, nil
+}
+
+// DeleteTaskResult discards the recorded outcome of a task, so that RunOnce runs it again.
+func (c *lockClient) DeleteTaskResult(ctx context.Context, params DeleteTaskResultParams) (DeleteTaskResultResult, error) {
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE key = $1;
+	`, c.options.TaskResultTableName)
+
+	result, err := c.db.ExecContext(ctx, deleteQuery, params.Key)
+	if err != nil {
+		return DeleteTaskResultResult{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return DeleteTaskResultResult{}, err
+	}
+
+	return DeleteTaskResultResult{Deleted: rowsAffected > 0}, nil
+}
+
+// PruneTaskResults deletes the expired outcomes, which are no longer returned but keep their rows until then.
+func (c *lockClient) PruneTaskResults(ctx context.Context, params PruneTaskResultsParams) (PruneTaskResultsResult, error) {
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE expires_at <= $1;
+	`, c.options.TaskResultTableName)
+
+	result, err := c.db.ExecContext(ctx, deleteQuery, time.Now())
+	if err != nil {
+		return PruneTaskResultsResult{}, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return PruneTaskResultsResult{}, err
+	}
+
+	return PruneTaskResultsResult{Deleted: deleted}, nil
+}
+
+// err returns the recorded error of a failed task, or nil.
+func (r TaskResult) err() error {
+	if !r.Failed {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrTaskFailed, r.Error)
+}
+
+type RunOnceParams struct {
+	Key               string        // [required] Task key: the task runs at most once per key
+	LockID            string        // [optional] LockID used while running the task. default: "<hostname>-<pid>-<sequence>"
+	TTLSeconds        int           // [optional] Lease TTL of the lock, renewed while the task runs. default: 30
+	FailureTTLSeconds int           // [optional] Time a failed run is recorded; later callers get ErrTaskFailed until then, and run the task again after. default: 60
+	PollInterval      time.Duration // [optional] Interval between checks while another process runs the task. default: 100ms
+}
+
+func (params *RunOnceParams) SetDefaults() {
+	if params.LockID == "" {
+		params.LockID = newProcessLockID()
+	}
+	if params.TTLSeconds <= 0 {
+		params.TTLSeconds = DefaultRunOnceTTLSeconds
+	}
+	if params.FailureTTLSeconds <= 0 {
+		params.FailureTTLSeconds = DefaultRunOnceFailureTTLSeconds
+	}
+	if params.PollInterval <= 0 {
+		params.PollInterval = DefaultRetryInterval
+	}
+}
+
+// RunOnce runs fn unless it has already run for params.Key in any process, and returns its outcome.
+// If another process is running it, RunOnce waits for that run to finish. Callers that did not run fn
+// get the recorded value, or ErrTaskFailed wrapping the recorded error message if fn failed within FailureTTLSeconds.
+// A successful run is recorded until its outcome is deleted with DeleteTaskResult (key RunOnceLockPrefix + params.Key).
+// If ctx is done or the lock is lost (ErrLockLost) while fn runs, no outcome is recorded and a later call runs fn again.
+// client must implement TaskResultStore.
+func RunOnce(ctx context.Context, client LockClient, params RunOnceParams, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	params.SetDefaults()
+	name := RunOnceLockPrefix + params.Key
+
+	results, err := storeOf[TaskResultStore](client)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		// 1. 이미 기록된 결과가 있으면 반환
+		getResult, err := results.GetTaskResult(ctx, GetTaskResultParams{Key: name})
+		if err != nil {
+			return nil, err
+		}
+		if getResult.Found {
+			return getResult.Result.Value, getResult.Result.err()
+		}
+
+		// 2. 실행자 선출
+		lockResult, err := client.TryXLock(ctx, TryXLockParams{
+			Name:       name,
+			LockID:     params.LockID,
+			TTLSeconds: params.TTLSeconds,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if lockResult.Acquired {
+			return runOnceLocked(ctx, client, results, params, name, lockResult.ExpiresAt, fn)
+		}
+
+		// 다른 프로세스가 실행 중이면 대기 후 재확인
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(params.PollInterval):
+		}
+	}
+}
+
+// runOnceLocked runs fn while holding the run-once lock and records its outcome.
+func runOnceLocked(
+	ctx context.Context,
+	client LockClient,
+	results TaskResultStore,
+	params RunOnceParams,
+	name string,
+	expiresAt time.Time,
+	fn func(ctx context.Context) ([]byte, error),
+) ([]byte, error) {
+	defer func() {
+		_, _ = client.Unlock(context.WithoutCancel(ctx), UnlockParams{Name: name, LockID: params.LockID})
+	}()
+
+	// 결과 확인과 잠금 사이에 다른 프로세스가 끝냈을 수 있으므로 재확인
+	getResult, err := results.GetTaskResult(ctx, GetTaskResultParams{Key: name})
+	if err != nil {
+		return nil, err
+	}
+	if getResult.Found {
+		return getResult.Result.Value, getResult.Result.err()
+	}
+
+	// 잠금을 잃으면 fn도 중단
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	lost := KeepAlive(runCtx, client, KeepAliveParams{
+		Name:       name,
+		LockID:     params.LockID,
+		TTLSeconds: params.TTLSeconds,
+		ExpiresAt:  expiresAt,
+	})
+	go func() {
+		if err, ok := <-lost; ok {
+			cancel(err)
+		}
+	}()
+
+	value, fnErr := fn(runCtx)
+
+	// 취소되었거나 잠금을 잃었으면 결과를 기록하지 않음
+	if cause := context.Cause(runCtx); cause != nil {
+		if fnErr != nil {
+			return nil, fnErr
+		}
+		return nil, cause
+	}
+
+	storeParams := StoreTaskResultParams{Key: name, Value: value}
+	if fnErr != nil {
+		storeParams = StoreTaskResultParams{Key: name, Failed: true, Error: fnErr.Error(), TTLSeconds: params.FailureTTLSeconds}
+	}
+
+	if _, err := results.StoreTaskResult(ctx, storeParams); err != nil {
+		return nil, err
+	}
+
+	return value, fnErr
+}
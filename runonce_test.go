@@ -0,0 +1,130 @@
+package pglock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunOnce_RecordsFirstResult tests that RunOnce runs a task once, records its result or failure and shares it with every caller
+func TestRunOnce_RecordsFirstResult(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	results := client.(TaskResultStore)
+	key := testLockName(t)
+
+	// 1. 결과는 처음 기록된 것만 유지
+	storeResult, err := results.StoreTaskResult(ctx, StoreTaskResultParams{Key: key + "/stored", Value: []byte("first")})
+	require.NoError(t, err)
+	assert.True(t, storeResult.Stored)
+
+	storeResult, err = results.StoreTaskResult(ctx, StoreTaskResultParams{Key: key + "/stored", Value: []byte("second")})
+	require.NoError(t, err)
+	assert.False(t, storeResult.Stored)
+	assert.Equal(t, []byte("first"), storeResult.Result.Value)
+
+	getResult, err := results.GetTaskResult(ctx, GetTaskResultParams{Key: key + "/stored"})
+	require.NoError(t, err)
+	assert.True(t, getResult.Found)
+	assert.Equal(t, key+"/stored", getResult.Result.Key)
+	assert.Equal(t, []byte("first"), getResult.Result.Value)
+
+	// 2. 동시에 호출해도 한 번만 실행되고 모두 같은 결과를 받음
+	var runs atomic.Int32
+	task := func(ctx context.Context) ([]byte, error) {
+		runs.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return []byte("done"), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := RunOnce(ctx, client, RunOnceParams{Key: key, PollInterval: 10 * time.Millisecond}, task)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("done"), value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), runs.Load())
+
+	value, err := RunOnce(ctx, client, RunOnceParams{Key: key}, task)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), value)
+	assert.Equal(t, int32(1), runs.Load())
+
+	// 3. 실패도 FailureTTLSeconds 동안 기록되어 이후 호출자는 기록된 오류를 받음
+	failingKey := testLockName(t) + "/failing"
+	_, err = RunOnce(ctx, client, RunOnceParams{Key: failingKey, FailureTTLSeconds: 1}, func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("migration failed")
+	})
+	assert.EqualError(t, err, "migration failed")
+
+	_, err = RunOnce(ctx, client, RunOnceParams{Key: failingKey}, task)
+	assert.ErrorIs(t, err, ErrTaskFailed)
+	assert.ErrorContains(t, err, "migration failed")
+	assert.Equal(t, int32(1), runs.Load())
+
+	getResult, err = results.GetTaskResult(ctx, GetTaskResultParams{Key: RunOnceLockPrefix + failingKey})
+	require.NoError(t, err)
+	assert.False(t, getResult.Result.ExpiresAt.IsZero())
+
+	// 실패 기록이 만료되면 다시 실행
+	backend.expire(1)
+
+	value, err = RunOnce(ctx, client, RunOnceParams{Key: failingKey}, task)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), value)
+	assert.Equal(t, int32(2), runs.Load())
+
+	// 4. 취소된 실행은 기록되지 않아 다음 호출에서 다시 실행
+	canceledKey := testLockName(t) + "/canceled"
+	cancelCtx, cancel := context.WithCancel(ctx)
+	_, err = RunOnce(cancelCtx, client, RunOnceParams{Key: canceledKey}, func(ctx context.Context) ([]byte, error) {
+		cancel()
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	value, err = RunOnce(ctx, client, RunOnceParams{Key: canceledKey}, task)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), value)
+	assert.Equal(t, int32(3), runs.Load())
+
+	// 5. Singleflight가 저장한 값은 RunOnce 결과로 보이지 않음
+	group := NewSingleflight(client, SingleflightOptions{})
+	value, err = group.Do(ctx, key+"/shared", func(ctx context.Context) ([]byte, error) {
+		return []byte("cached"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cached"), value)
+
+	value, err = RunOnce(ctx, client, RunOnceParams{Key: SingleflightPrefix + key + "/shared"}, task)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), value)
+	assert.Equal(t, int32(4), runs.Load())
+
+	// 6. 기록을 삭제하면 다시 실행
+	deleteResult, err := results.DeleteTaskResult(ctx, DeleteTaskResultParams{Key: RunOnceLockPrefix + key})
+	require.NoError(t, err)
+	assert.True(t, deleteResult.Deleted)
+
+	value, err = RunOnce(ctx, client, RunOnceParams{Key: key}, task)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), value)
+	assert.Equal(t, int32(5), runs.Load())
+
+	deleteResult, err = results.DeleteTaskResult(ctx, DeleteTaskResultParams{Key: testLockName(t) + "/missing"})
+	require.NoError(t, err)
+	assert.False(t, deleteResult.Deleted)
+}
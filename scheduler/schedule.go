@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule tells when a job is due. Every replica must compute the same ticks,
+// so a schedule may only depend on the time it is given.
+type Schedule interface {
+	// Next returns the first tick strictly after t, or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// intervalSchedule ticks at every multiple of an interval since the Unix epoch
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every returns a schedule that ticks every interval. Ticks are aligned to the Unix epoch
+// (e.g. Every(time.Hour) ticks on the hour in UTC), so that all replicas agree on them.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("scheduler: interval must be positive")
+	}
+
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	// Truncate는 Go의 zero time(1년 1월 1일) 기준이므로 epoch부터의 경과 시간으로 계산
+	epoch := time.Unix(0, 0).In(t.Location())
+	elapsed := t.Sub(epoch)
+
+	ticks := elapsed / s.interval
+	if elapsed < 0 && elapsed%s.interval != 0 {
+		ticks--
+	}
+
+	return epoch.Add((ticks + 1) * s.interval)
+}
+
+// cronSchedule is a parsed cron expression; each field is the set of values it matches
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// 둘 다 제한되어 있으면 cron 규칙에 따라 둘 중 하나만 맞아도 실행
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
+
+	location *time.Location
+}
+
+// cronField is the range of values of a cron field
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7}, // 0과 7 모두 일요일
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron parses a standard five-field cron expression ("minute hour day-of-month month day-of-week"),
+// evaluated in UTC. Fields accept "*", values, ranges ("1-5"), lists ("1,15") and steps ("*/10", "0-30/5").
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are also accepted.
+func Cron(expression string) (Schedule, error) {
+	return CronIn(expression, time.UTC)
+}
+
+// CronIn parses a cron expression like Cron, evaluated in location.
+func CronIn(expression string, location *time.Location) (Schedule, error) {
+	if descriptor, ok := cronDescriptors[strings.TrimSpace(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("scheduler: cron expression %q must have %d fields", expression, len(cronFields))
+	}
+
+	values := make([]uint64, len(fields))
+	for i, field := range fields {
+		bits, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: cron expression %q: %w", expression, err)
+		}
+		values[i] = bits
+	}
+
+	// 7(일요일)은 0으로 취급
+	dayOfWeek := values[4]
+	if dayOfWeek&(1<<7) != 0 {
+		dayOfWeek |= 1
+	}
+
+	return cronSchedule{
+		minute:        values[0],
+		hour:          values[1],
+		dayOfMonth:    values[2],
+		month:         values[3],
+		dayOfWeek:     dayOfWeek,
+		dayOfMonthAny: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekAny:  strings.HasPrefix(fields[4], "*"),
+		location:      location,
+	}, nil
+}
+
+// MustCron is like Cron but panics if the expression is invalid.
+func MustCron(expression string) Schedule {
+	schedule, err := Cron(expression)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
+
+// parseCronField returns the set of values matched by a comma-separated cron field.
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, spec.name)
+			}
+		}
+
+		low, high := spec.min, spec.max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			low, err = parseCronValue(lowPart, spec)
+			if err != nil {
+				return 0, err
+			}
+
+			high = low
+			if isRange {
+				high, err = parseCronValue(highPart, spec)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/15"는 5부터 끝까지 15 간격
+				high = spec.max
+			}
+
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangePart, spec.name)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < spec.min || number > spec.max {
+		return 0, fmt.Errorf("invalid value %q in %s field (%d-%d)", value, spec.name, spec.min, spec.max)
+	}
+
+	return number, nil
+}
+
+// cronSearchLimit bounds the search for the next tick of expressions that never match (e.g. "0 0 31 2 *")
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if s.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := s.dayOfWeek&(1<<int(t.Weekday())) != 0
+
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
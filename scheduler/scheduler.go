@@ -0,0 +1,295 @@
+// Package scheduler runs scheduled jobs on exactly one replica per tick.
+//
+// Every replica registers the same jobs and runs the scheduler. When a tick is due, the replicas
+// race for the pglock exclusive lock of the job; the winner checks the job status recorded in the
+// job status table, runs the job if nobody has run that tick yet, and records the outcome.
+// Ticks that no replica ran (e.g. during an outage or while a run overran) are detected as missed.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/myyrakle/pglock"
+)
+
+const (
+	// LockPrefix is prepended to the job name to form the name of the lock guarding its runs
+	LockPrefix = "scheduler/"
+
+	// DefaultLockTTLSeconds is the default lease TTL of the lock held while a job runs
+	DefaultLockTTLSeconds = 30
+)
+
+// maxMissedRuns bounds the missed ticks counted at once, so that a long outage of a frequent job stays cheap to count
+const maxMissedRuns = 10000
+
+var replicaSequence atomic.Int64
+
+type Options struct {
+	ReplicaID      string                                                    // [optional] LockID of this replica, recorded as RunBy. default: "<hostname>-<pid>-<sequence>"
+	LockTTLSeconds int                                                       // [optional] Lease TTL of the job lock, renewed while the job runs. default: 30
+	OnError        func(job string, err error)                               // [optional] Receives the errors of job runs and of the scheduler itself. default: ignored
+	OnMissed       func(job string, missedRuns int64, scheduledAt time.Time) // [optional] Called before a run when ticks since the previous run were missed. default: ignored
+}
+
+func (options *Options) SetDefaults() {
+	if options.ReplicaID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "unknown"
+		}
+		options.ReplicaID = fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), replicaSequence.Add(1))
+	}
+	if options.LockTTLSeconds <= 0 {
+		options.LockTTLSeconds = DefaultLockTTLSeconds
+	}
+	if options.OnError == nil {
+		options.OnError = func(string, error) {}
+	}
+	if options.OnMissed == nil {
+		options.OnMissed = func(string, int64, time.Time) {}
+	}
+}
+
+// Job is a task run on one replica at every tick of its schedule.
+type Job struct {
+	Name     string                                                 // [required] Unique job name, used for its lock and status
+	Schedule Schedule                                               // [required] When the job is due: Every(interval) or a Cron expression
+	Run      func(ctx context.Context, scheduledAt time.Time) error // [required] The task; scheduledAt is the tick being run
+}
+
+// Scheduler runs registered jobs so that each tick is executed by exactly one replica.
+type Scheduler struct {
+	client   pglock.LockClient
+	statuses pglock.JobStatusStore // nil if client does not implement it
+	options  Options
+
+	mu      sync.Mutex
+	jobs    []Job
+	running bool
+}
+
+// New returns a Scheduler that coordinates through client, which must implement pglock.JobStatusStore.
+func New(client pglock.LockClient, options Options) *Scheduler {
+	options.SetDefaults()
+	statuses, _ := client.(pglock.JobStatusStore)
+
+	return &Scheduler{
+		client:   client,
+		statuses: statuses,
+		options:  options,
+	}
+}
+
+// errNoJobStatusStore is returned when the client cannot record job statuses
+var errNoJobStatusStore = fmt.Errorf("scheduler: client does not implement pglock.JobStatusStore: %w", pglock.ErrNotSupported)
+
+// Register adds a job. Jobs must be registered before Run is called.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return errors.New("scheduler: job requires Name, Schedule and Run")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return errors.New("scheduler: cannot register jobs while running")
+	}
+	for _, registered := range s.jobs {
+		if registered.Name == job.Name {
+			return fmt.Errorf("scheduler: job %q is already registered", job.Name)
+		}
+	}
+
+	s.jobs = append(s.jobs, job)
+
+	return nil
+}
+
+// Run schedules the registered jobs until ctx is done, then waits for the running jobs to return.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.statuses == nil {
+		return errNoJobStatusStore
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return errors.New("scheduler: already running")
+	}
+	s.running = true
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runJob(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// Status returns the last run of a job as recorded by any replica, and whether the job has run before.
+func (s *Scheduler) Status(ctx context.Context, name string) (pglock.JobStatus, bool, error) {
+	if s.statuses == nil {
+		return pglock.JobStatus{}, false, errNoJobStatusStore
+	}
+
+	result, err := s.statuses.GetJobStatus(ctx, pglock.GetJobStatusParams{Name: name})
+	if err != nil {
+		return pglock.JobStatus{}, false, err
+	}
+
+	return result.Status, result.Found, nil
+}
+
+// runJob waits for the ticks of a job and runs each of them until ctx is done.
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	for {
+		tick := job.Schedule.Next(time.Now())
+		if tick.IsZero() {
+			// 더 이상 실행 시각이 없음
+			return
+		}
+
+		timer := time.NewTimer(time.Until(tick))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runTick(ctx, job, tick)
+	}
+}
+
+// runTick runs a tick of a job unless another replica is running the job or has already run the tick.
+func (s *Scheduler) runTick(ctx context.Context, job Job, tick time.Time) {
+	lockName := LockPrefix + job.Name
+
+	// 1. 잠금을 얻은 레플리카만 실행 (다른 레플리카가 실행 중이면 건너뜀)
+	lockResult, err := s.client.TryXLock(ctx, pglock.TryXLockParams{
+		Name:       lockName,
+		LockID:     s.options.ReplicaID,
+		TTLSeconds: s.options.LockTTLSeconds,
+	})
+	if err != nil {
+		s.options.OnError(job.Name, err)
+		return
+	}
+	if !lockResult.Acquired {
+		return
+	}
+	defer func() {
+		if _, err := s.client.Unlock(context.WithoutCancel(ctx), pglock.UnlockParams{Name: lockName, LockID: s.options.ReplicaID}); err != nil {
+			s.options.OnError(job.Name, err)
+		}
+	}()
+
+	// 2. 이미 실행된 tick이면 건너뜀
+	statusResult, err := s.statuses.GetJobStatus(ctx, pglock.GetJobStatusParams{Name: job.Name})
+	if err != nil {
+		s.options.OnError(job.Name, err)
+		return
+	}
+	if statusResult.Found && !statusResult.Status.ScheduledAt.Before(tick) {
+		return
+	}
+
+	// 3. 이전 실행 이후 놓친 tick 집계
+	var missedRuns int64
+	if statusResult.Found {
+		missedRuns = countMissedRuns(job.Schedule, statusResult.Status.ScheduledAt, tick)
+		if missedRuns > 0 {
+			s.options.OnMissed(job.Name, missedRuns, tick)
+		}
+	}
+
+	// 4. 실행 시작을 먼저 기록해 다른 레플리카가 같은 tick을 실행하지 않도록 함
+	status := pglock.JobStatus{
+		Name:        job.Name,
+		ScheduledAt: tick,
+		StartedAt:   time.Now(),
+		RunBy:       s.options.ReplicaID,
+		State:       pglock.JobStateRunning,
+		MissedRuns:  statusResult.Status.MissedRuns + missedRuns,
+	}
+	saveResult, err := s.statuses.SaveJobStatus(ctx, pglock.SaveJobStatusParams{Status: status})
+	if err != nil {
+		s.options.OnError(job.Name, err)
+		return
+	}
+	if !saveResult.Saved {
+		// 다른 레플리카가 이후 tick을 이미 기록함
+		return
+	}
+
+	// 5. 잠금을 유지하며 실행, 잠금을 잃으면 실행도 중단
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	lost := pglock.KeepAlive(runCtx, s.client, pglock.KeepAliveParams{
+		Name:       lockName,
+		LockID:     s.options.ReplicaID,
+		TTLSeconds: s.options.LockTTLSeconds,
+		ExpiresAt:  lockResult.ExpiresAt,
+	})
+	go func() {
+		if err, ok := <-lost; ok {
+			s.options.OnError(job.Name, err)
+			cancel(err)
+		}
+	}()
+
+	runErr := job.Run(runCtx, tick)
+
+	// 6. 잠금을 잃었으면 다른 레플리카가 이후 tick을 실행했을 수 있으므로 결과를 기록하지 않음
+	if errors.Is(context.Cause(runCtx), pglock.ErrLockLost) {
+		return
+	}
+
+	// 7. 결과 기록
+	status.FinishedAt = time.Now()
+	status.State = pglock.JobStateSucceeded
+	if runErr != nil {
+		status.State = pglock.JobStateFailed
+		status.Error = runErr.Error()
+		s.options.OnError(job.Name, runErr)
+	}
+	if _, err := s.statuses.SaveJobStatus(context.WithoutCancel(ctx), pglock.SaveJobStatusParams{Status: status}); err != nil {
+		s.options.OnError(job.Name, err)
+	}
+}
+
+// countMissedRuns returns the number of ticks strictly between the last run and tick.
+func countMissedRuns(schedule Schedule, lastScheduledAt time.Time, tick time.Time) int64 {
+	var missedRuns int64
+
+	for next := schedule.Next(lastScheduledAt); !next.IsZero() && next.Before(tick); next = schedule.Next(next) {
+		missedRuns++
+		if missedRuns >= maxMissedRuns {
+			break
+		}
+	}
+
+	return missedRuns
+}
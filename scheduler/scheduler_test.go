@@ -0,0 +1,290 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/myyrakle/pglock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScheduler_RunsEachTickOnce tests that every tick of a job runs on exactly one of the replicas
+func TestScheduler_RunsEachTickOnce(t *testing.T) {
+	client := pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var mu sync.Mutex
+	runs := map[time.Time]int{}
+	job := Job{
+		Name:     "report",
+		Schedule: Every(50 * time.Millisecond),
+		Run: func(ctx context.Context, scheduledAt time.Time) error {
+			mu.Lock()
+			runs[scheduledAt]++
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	// 1. 같은 job을 등록한 레플리카 3개 실행
+	var wg sync.WaitGroup
+	for _, replicaID := range []string{"replica_1", "replica_2", "replica_3"} {
+		replica := New(client, Options{ReplicaID: replicaID})
+		require.NoError(t, replica.Register(job))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.ErrorIs(t, replica.Run(ctx), context.DeadlineExceeded)
+		}()
+	}
+	wg.Wait()
+
+	// 2. 모든 tick이 한 번씩만 실행되어야 함
+	mu.Lock()
+	defer mu.Unlock()
+	assert.GreaterOrEqual(t, len(runs), 5)
+	for tick, count := range runs {
+		assert.Equal(t, 1, count, "tick %s", tick)
+	}
+
+	// 3. 마지막 실행 상태가 기록되어야 함
+	status, found, err := New(client, Options{}).Status(context.Background(), "report")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, pglock.JobStateSucceeded, status.State)
+	assert.Contains(t, []string{"replica_1", "replica_2", "replica_3"}, status.RunBy)
+	assert.Equal(t, 1, runs[status.ScheduledAt])
+}
+
+// TestScheduler_RecordsMissedRunsAndFailures tests that ticks missed since the last recorded run are counted and that failed runs are recorded
+func TestScheduler_RecordsMissedRunsAndFailures(t *testing.T) {
+	client := pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 50 * time.Millisecond
+	schedule := Every(interval)
+
+	// 1. 10 tick 전에 마지막으로 실행된 것으로 기록
+	lastScheduledAt := time.Now().Truncate(interval).Add(-10 * interval)
+	_, err := client.(pglock.JobStatusStore).SaveJobStatus(ctx, pglock.SaveJobStatusParams{Status: pglock.JobStatus{
+		Name:        "cleanup",
+		ScheduledAt: lastScheduledAt,
+		StartedAt:   lastScheduledAt,
+		FinishedAt:  lastScheduledAt,
+		RunBy:       "old_replica",
+		State:       pglock.JobStateSucceeded,
+		MissedRuns:  2,
+	}})
+	require.NoError(t, err)
+
+	missed := make(chan int64, 1)
+	failed := make(chan time.Time, 1)
+	scheduler := New(client, Options{
+		ReplicaID: "replica_1",
+		OnMissed: func(job string, missedRuns int64, scheduledAt time.Time) {
+			missed <- missedRuns
+		},
+	})
+	require.NoError(t, scheduler.Register(Job{
+		Name:     "cleanup",
+		Schedule: schedule,
+		Run: func(ctx context.Context, scheduledAt time.Time) error {
+			select {
+			case failed <- scheduledAt:
+			default:
+			}
+			return errors.New("disk full")
+		},
+	}))
+	require.Error(t, scheduler.Register(Job{Name: "cleanup", Schedule: schedule, Run: func(context.Context, time.Time) error { return nil }}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- scheduler.Run(ctx)
+	}()
+
+	// 2. 첫 실행 전에 놓친 tick 수가 보고되어야 함
+	var missedRuns int64
+	select {
+	case missedRuns = <-missed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("missed runs were not reported")
+	}
+	assert.GreaterOrEqual(t, missedRuns, int64(9))
+
+	var scheduledAt time.Time
+	select {
+	case scheduledAt = <-failed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// 3. 실패 결과와 누적 missed 수가 기록되어야 함
+	status, found, err := scheduler.Status(context.Background(), "cleanup")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, pglock.JobStateFailed, status.State)
+	assert.Equal(t, "disk full", status.Error)
+	assert.Equal(t, "replica_1", status.RunBy)
+	assert.False(t, status.ScheduledAt.Before(scheduledAt))
+	assert.GreaterOrEqual(t, status.MissedRuns, 2+missedRuns)
+}
+
+// TestScheduler_LostLockKeepsNewerStatus tests that a replica that lost the job lock mid-run does not overwrite the status recorded by the replica that took over
+func TestScheduler_LostLockKeepsNewerStatus(t *testing.T) {
+	client := pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})
+	statuses := client.(pglock.JobStatusStore)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan time.Time, 1)
+	lost := make(chan error, 1)
+	scheduler := New(client, Options{
+		ReplicaID:      "replica_1",
+		LockTTLSeconds: 1,
+		OnError: func(job string, err error) {
+			if errors.Is(err, pglock.ErrLockLost) {
+				lost <- err
+			}
+		},
+	})
+	require.NoError(t, scheduler.Register(Job{
+		Name:     "export",
+		Schedule: Every(50 * time.Millisecond),
+		Run: func(ctx context.Context, scheduledAt time.Time) error {
+			select {
+			case started <- scheduledAt:
+			default:
+			}
+			<-ctx.Done()
+			return context.Cause(ctx)
+		},
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- scheduler.Run(ctx)
+	}()
+
+	var tick time.Time
+	select {
+	case tick = <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+
+	// 1. 실행 중 잠금을 빼앗기고, 다른 레플리카가 이후 tick을 기록
+	_, err := client.ForceUnlock(ctx, pglock.ForceUnlockParams{Name: LockPrefix + "export"})
+	require.NoError(t, err)
+	newer := pglock.JobStatus{
+		Name:        "export",
+		ScheduledAt: tick.Add(time.Hour),
+		StartedAt:   time.Now(),
+		RunBy:       "replica_2",
+		State:       pglock.JobStateRunning,
+	}
+	saveResult, err := statuses.SaveJobStatus(ctx, pglock.SaveJobStatusParams{Status: newer})
+	require.NoError(t, err)
+	require.True(t, saveResult.Saved)
+
+	select {
+	case <-lost:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock loss was not reported")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// 2. 잠금을 잃은 레플리카의 결과는 기록되지 않음
+	status, found, err := scheduler.Status(context.Background(), "export")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "replica_2", status.RunBy)
+	assert.Equal(t, pglock.JobStateRunning, status.State)
+	assert.True(t, newer.ScheduledAt.Equal(status.ScheduledAt))
+
+	// 3. 이전 tick의 상태는 이후 tick의 상태를 덮어쓰지 않음
+	saveResult, err = statuses.SaveJobStatus(context.Background(), pglock.SaveJobStatusParams{Status: pglock.JobStatus{Name: "export", ScheduledAt: tick}})
+	require.NoError(t, err)
+	assert.False(t, saveResult.Saved)
+}
+
+// TestScheduler_RequiresJobStatusStore tests that a client without job statuses is refused instead of running ticks twice
+func TestScheduler_RequiresJobStatusStore(t *testing.T) {
+	// LockClient만 노출하도록 감싸 JobStatusStore를 숨김
+	client := struct{ pglock.LockClient }{pglock.NewMemoryLockClient(pglock.MemoryLockClientOptions{})}
+	scheduler := New(client, Options{})
+
+	assert.ErrorIs(t, scheduler.Run(context.Background()), pglock.ErrNotSupported)
+
+	_, _, err := scheduler.Status(context.Background(), "cleanup")
+	assert.ErrorIs(t, err, pglock.ErrNotSupported)
+}
+
+// TestEvery_Next tests that interval ticks are aligned to the Unix epoch
+func TestEvery_Next(t *testing.T) {
+	epoch := time.Unix(0, 0).UTC()
+
+	tests := []struct {
+		interval time.Duration
+		from     time.Time
+		expected time.Time
+	}{
+		{time.Hour, time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC), time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{7 * time.Hour, epoch, epoch.Add(7 * time.Hour)}, // zero time 기준이면 epoch와 어긋나는 간격
+		{7 * time.Hour, epoch.Add(10 * time.Hour), epoch.Add(14 * time.Hour)},
+		{7 * time.Hour, epoch.Add(-time.Hour), epoch},
+		{7 * time.Hour, epoch.Add(-7 * time.Hour), epoch},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Every(test.interval).Next(test.from), "%s from %s", test.interval, test.from)
+	}
+}
+
+// TestCron_Next tests the ticks computed for cron expressions
+func TestCron_Next(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC) // 수요일
+
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, time.February, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 1", time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 5", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)}, // 1일/15일 또는 금요일
+		{"@monthly", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := Cron(test.expression)
+		require.NoError(t, err, test.expression)
+		assert.Equal(t, test.expected, schedule.Next(from), test.expression)
+	}
+
+	// 존재하지 않는 날짜는 실행되지 않음
+	schedule, err := Cron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(from).IsZero())
+
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := Cron(expression)
+		assert.Error(t, err, expression)
+	}
+}
@@ -0,0 +1,267 @@
+package pglock
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// A session groups the locks of one client process. Locks acquired with a SessionID are held
+// until their own TTL or the end of the session, whichever comes first: once the session is
+// closed or misses its heartbeats, every lock bound to it is treated as expired.
+
+func (c *lockClient) createLockSessionTable(ctx context.Context) error {
+	tableName := c.options.LockSessionTableName
+
+	createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+	`, tableName)
+
+	_, err := c.db.ExecContext(ctx, createTableSQL)
+
+	return err
+}
+
+// newSessionID returns a random session ID. Session IDs are never reused,
+// so locks bound to a closed session can never come back to life.
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+type OpenSessionParams struct {
+	TTLSeconds int // Time-To-Live: the session ends unless it is refreshed within this duration
+}
+
+type OpenSessionResult struct {
+	SessionID string    // Identifier to pass as SessionID when acquiring locks
+	ExpiresAt time.Time // Expiration time of the session
+}
+
+// OpenSession registers a new session. Keep it alive with RefreshSession (or KeepSessionAlive).
+func (c *lockClient) OpenSession(ctx context.Context, params OpenSessionParams) (OpenSessionResult, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return OpenSessionResult{}, err
+	}
+
+	tableName := c.options.LockSessionTableName
+	expiresAt := time.Now().Add(time.Duration(params.TTLSeconds) * time.Second)
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (id, expires_at)
+		VALUES ($1, $2);
+	`, tableName)
+	if _, err := c.db.ExecContext(ctx, insertQuery, sessionID, expiresAt); err != nil {
+		return OpenSessionResult{}, err
+	}
+
+	// 만료된 세션 행 정리 - 행이 없는 세션도 종료된 것으로 취급하므로 안전
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE expires_at < $1;
+	`, tableName)
+	if _, err := c.db.ExecContext(ctx, deleteQuery, time.Now()); err != nil {
+		return OpenSessionResult{}, err
+	}
+
+	return OpenSessionResult{SessionID: sessionID, ExpiresAt: expiresAt}, nil
+}
+
+type RefreshSessionParams struct {
+	SessionID  string // Session to keep alive
+	TTLSeconds int    // Time-To-Live: new duration in seconds for the session, counted from now
+}
+
+type RefreshSessionResult struct {
+	ExpiresAt time.Time // New expiration time of the session
+	Refreshed bool      // Whether the session was still alive and has been extended
+}
+
+// RefreshSession extends a session that is still alive. An expired session cannot be revived;
+// Refreshed is false in that case and the locks bound to it are already released.
+func (c *lockClient) RefreshSession(ctx context.Context, params RefreshSessionParams) (RefreshSessionResult, error) {
+	tableName := c.options.LockSessionTableName
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(params.TTLSeconds) * time.Second)
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET expires_at = $1
+		WHERE id = $2 AND expires_at > $3;
+	`, tableName)
+	result, err := c.db.ExecContext(ctx, updateQuery, expiresAt, params.SessionID, now)
+	if err != nil {
+		return RefreshSessionResult{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return RefreshSessionResult{}, err
+	}
+	if rowsAffected == 0 {
+		return RefreshSessionResult{Refreshed: false}, nil
+	}
+
+	return RefreshSessionResult{ExpiresAt: expiresAt, Refreshed: true}, nil
+}
+
+type CloseSessionParams struct {
+	SessionID string // Session to close
+}
+
+type CloseSessionResult struct {
+	Closed bool // Whether the session was still alive
+}
+
+// CloseSession ends a session, releasing every lock bound to it.
+func (c *lockClient) CloseSession(ctx context.Context, params CloseSessionParams) (CloseSessionResult, error) {
+	tableName := c.options.LockSessionTableName
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE id = $1
+		RETURNING expires_at;
+	`, tableName)
+
+	var expiresAt time.Time
+	err := c.db.QueryRowContext(ctx, deleteQuery, params.SessionID).Scan(&expiresAt)
+	if err == sql.ErrNoRows {
+		return CloseSessionResult{Closed: false}, nil
+	}
+	if err != nil {
+		return CloseSessionResult{}, err
+	}
+
+	return CloseSessionResult{Closed: expiresAt.After(time.Now())}, nil
+}
+
+// sessionAlive reports whether a session exists and has not expired at now.
+func (c *lockClient) sessionAlive(ctx context.Context, queryer rowQueryer, sessionID string, now time.Time) (bool, error) {
+	tableName := c.options.LockSessionTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT expires_at
+		FROM %s
+		WHERE id = $1;
+	`, tableName)
+
+	var expiresAt time.Time
+	err := queryer.QueryRowContext(ctx, selectQuery, sessionID).Scan(&expiresAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return expiresAt.After(now), nil
+}
+
+// checkSession returns ErrSessionLost if a lock is requested under a session that is no longer alive.
+func (c *lockClient) checkSession(ctx context.Context, queryer rowQueryer, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+
+	alive, err := c.sessionAlive(ctx, queryer, sessionID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !alive {
+		return ErrSessionLost
+	}
+
+	return nil
+}
+
+// expireDeadSessionHolders marks the holders of a lock row whose session is no longer alive as expired at now,
+// so that the usual expiry checks release them regardless of their own TTL.
+func (c *lockClient) expireDeadSessionHolders(ctx context.Context, queryer rowQueryer, row *lockRow, now time.Time) error {
+	// 1. 아직 만료되지 않은 보유자가 묶인 세션 수집
+	sessionIDs := row.liveSessionIDs(now)
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	// 2. 살아 있는 세션을 한 번에 조회
+	alive, err := c.aliveSessions(ctx, queryer, sessionIDs, now)
+	if err != nil {
+		return err
+	}
+
+	// 3. 종료된 세션에 묶인 보유자 만료 처리
+	row.expireDeadSessionHolders(func(sessionID string) bool { return alive[sessionID] }, now)
+
+	return nil
+}
+
+// aliveSessions returns which of the given sessions exist and have not expired at now, in a single query.
+func (c *lockClient) aliveSessions(ctx context.Context, queryer rowQueryer, sessionIDs []string, now time.Time) (map[string]bool, error) {
+	tableName := c.options.LockSessionTableName
+
+	selectQuery := fmt.Sprintf(`
+		SELECT COALESCE(array_agg(id), '{}')
+		FROM %s
+		WHERE id = ANY($1) AND expires_at > $2;
+	`, tableName)
+
+	var aliveIDs pq.StringArray
+	if err := queryer.QueryRowContext(ctx, selectQuery, pq.Array(sessionIDs), now).Scan(&aliveIDs); err != nil {
+		return nil, err
+	}
+
+	alive := make(map[string]bool, len(aliveIDs))
+	for _, sessionID := range aliveIDs {
+		alive[sessionID] = true
+	}
+
+	return alive, nil
+}
+
+type KeepSessionAliveParams struct {
+	SessionID        string        // Session to keep alive
+	TTLSeconds       int           // Time-To-Live set on every refresh
+	ExpiresAt        time.Time     // Current expiration time of the session (default value: now + TTL)
+	IntervalDuration time.Duration // Refresh interval (default value: TTL / 3)
+}
+
+// KeepSessionAlive refreshes a session in the background until ctx is done or the session is lost.
+// The returned channel receives ErrSessionLost (wrapping the last refresh error, if any) when
+// the session could not be kept, and is closed when refreshing stops for any reason.
+// Failed refreshes are retried until the session's known expiration time passes.
+func KeepSessionAlive(ctx context.Context, client LockClient, params KeepSessionAliveParams) <-chan error {
+	lease := renewedLease{
+		TTLSeconds:       params.TTLSeconds,
+		ExpiresAt:        params.ExpiresAt,
+		IntervalDuration: params.IntervalDuration,
+		ErrLost:          ErrSessionLost,
+	}
+
+	return lease.keep(ctx, func(ctx context.Context) (time.Time, bool, error) {
+		result, err := client.RefreshSession(ctx, RefreshSessionParams{
+			SessionID:  params.SessionID,
+			TTLSeconds: params.TTLSeconds,
+		})
+
+		return result.ExpiresAt, result.Refreshed, err
+	})
+}
+
+// nullSessionID stores an empty SessionID as NULL.
+func nullSessionID(sessionID string) sql.NullString {
+	return sql.NullString{String: sessionID, Valid: sessionID != ""}
+}
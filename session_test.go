@@ -0,0 +1,92 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSession_ReleasesBoundLocks tests that locks bound to a session are released once the session is closed or stops sending heartbeats
+func TestSession_ReleasesBoundLocks(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	xName := testLockName(t) + "/x"
+	sName := testLockName(t) + "/s"
+
+	// 1. 세션에 묶인 락은 TTL이 길어도 세션이 닫히면 해제됨
+	session, err := client.OpenSession(ctx, OpenSessionParams{TTLSeconds: 60})
+	require.NoError(t, err)
+	require.NotEmpty(t, session.SessionID)
+
+	xResult, err := client.TryXLock(ctx, TryXLockParams{Name: xName, LockID: "holder", TTLSeconds: 600, SessionID: session.SessionID})
+	require.NoError(t, err)
+	require.True(t, xResult.Acquired)
+	sResult, err := client.TrySLock(ctx, TrySLockParams{Name: sName, LockID: "holder", TTLSeconds: 600, MaxSharedLocks: -1, SessionID: session.SessionID})
+	require.NoError(t, err)
+	require.True(t, sResult.Acquired)
+
+	assert.False(t, tryXLock(t, client, xName, "other", 60))
+	assert.False(t, tryXLock(t, client, sName, "other", 60))
+
+	describeResult, err := client.DescribeLock(ctx, DescribeLockParams{Name: xName})
+	require.NoError(t, err)
+	assert.Equal(t, session.SessionID, describeResult.Lock.XSessionID)
+
+	closeResult, err := client.CloseSession(ctx, CloseSessionParams{SessionID: session.SessionID})
+	require.NoError(t, err)
+	assert.True(t, closeResult.Closed)
+
+	refreshResult, err := client.Refresh(ctx, RefreshParams{Name: xName, LockID: "holder", TTLSeconds: 600})
+	require.NoError(t, err)
+	assert.False(t, refreshResult.Refreshed)
+
+	assert.True(t, tryXLock(t, client, xName, "other", 60))
+	assert.True(t, tryXLock(t, client, sName, "other", 60))
+
+	// 2. 닫힌 세션으로는 획득할 수 없음
+	_, err = client.TryXLock(ctx, TryXLockParams{Name: testLockName(t) + "/closed", LockID: "holder", TTLSeconds: 60, SessionID: session.SessionID})
+	assert.ErrorIs(t, err, ErrSessionLost)
+
+	// 3. heartbeat가 끊긴 세션도 종료된 것으로 취급
+	expiringName := testLockName(t) + "/expiring"
+	session, err = client.OpenSession(ctx, OpenSessionParams{TTLSeconds: 1})
+	require.NoError(t, err)
+
+	xResult, err = client.TryXLock(ctx, TryXLockParams{Name: expiringName, LockID: "holder", TTLSeconds: 600, SessionID: session.SessionID})
+	require.NoError(t, err)
+	require.True(t, xResult.Acquired)
+
+	backend.expire(1)
+
+	sessionResult, err := client.RefreshSession(ctx, RefreshSessionParams{SessionID: session.SessionID, TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, sessionResult.Refreshed)
+	assert.True(t, tryXLock(t, client, expiringName, "other", 60))
+
+	// 4. 블로킹 획득도 세션에 묶임
+	blockingXName := testLockName(t) + "/blocking-x"
+	blockingSName := testLockName(t) + "/blocking-s"
+	session, err = client.OpenSession(ctx, OpenSessionParams{TTLSeconds: 60})
+	require.NoError(t, err)
+
+	_, err = client.XLock(ctx, XLockParams{Name: blockingXName, LockID: "holder", TTLSeconds: 600, SessionID: session.SessionID})
+	require.NoError(t, err)
+	_, err = client.SLock(ctx, SLockParams{Name: blockingSName, LockID: "holder", TTLSeconds: 600, MaxSharedLocks: -1, SessionID: session.SessionID})
+	require.NoError(t, err)
+
+	describeResult, err = client.DescribeLock(ctx, DescribeLockParams{Name: blockingXName})
+	require.NoError(t, err)
+	assert.Equal(t, session.SessionID, describeResult.Lock.XSessionID)
+	describeResult, err = client.DescribeLock(ctx, DescribeLockParams{Name: blockingSName})
+	require.NoError(t, err)
+	require.Len(t, describeResult.Lock.SharedLocks, 1)
+	assert.Equal(t, session.SessionID, describeResult.Lock.SharedLocks[0].SessionID)
+
+	_, err = client.CloseSession(ctx, CloseSessionParams{SessionID: session.SessionID})
+	require.NoError(t, err)
+	assert.True(t, tryXLock(t, client, blockingXName, "other", 60))
+	assert.True(t, tryXLock(t, client, blockingSName, "other", 60))
+}
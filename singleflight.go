@@ -0,0 +1,147 @@
+package pglock
+
+import (
+	"context"
+	"time"
+)
+
+// Singleflight deduplicates an expensive computation across processes: the process that gets the
+// exclusive lock "singleflight/<key>" computes the value and stores it in the task result table
+// with an expiry, and the processes waiting for it return the stored value instead of computing it again.
+
+// SingleflightPrefix is prepended to the key to form the lock name and the task result key
+const SingleflightPrefix = "singleflight/"
+
+const (
+	// DefaultSingleflightResultTTLSeconds is the default time a computed value is shared
+	DefaultSingleflightResultTTLSeconds = 60
+	// DefaultSingleflightLockTTLSeconds is the default lease TTL of the lock held while a value is computed
+	DefaultSingleflightLockTTLSeconds = 30
+)
+
+type SingleflightOptions struct {
+	ResultTTLSeconds int           // [optional] Time a computed value is returned to other callers. default: 60
+	LockTTLSeconds   int           // [optional] Lease TTL of the lock, renewed while the value is computed. default: 30
+	PollInterval     time.Duration // [optional] Interval between checks while another process computes the value. default: 100ms
+}
+
+func (options *SingleflightOptions) SetDefaults() {
+	if options.ResultTTLSeconds <= 0 {
+		options.ResultTTLSeconds = DefaultSingleflightResultTTLSeconds
+	}
+	if options.LockTTLSeconds <= 0 {
+		options.LockTTLSeconds = DefaultSingleflightLockTTLSeconds
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultRetryInterval
+	}
+}
+
+// Singleflight makes concurrent callers in different processes share one computation per key.
+type Singleflight struct {
+	client  LockClient
+	options SingleflightOptions
+}
+
+// NewSingleflight returns a Singleflight that coordinates through client, which must implement TaskResultStore.
+func NewSingleflight(client LockClient, options SingleflightOptions) *Singleflight {
+	options.SetDefaults()
+
+	return &Singleflight{client: client, options: options}
+}
+
+// Do returns the value stored for key if it has not expired, and otherwise computes it with fn,
+// waiting instead if another process is already computing it.
+// Errors of fn are returned to its caller only and are not stored: a waiting caller then
+// computes the value itself, one at a time.
+func (s *Singleflight) Do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	name := SingleflightPrefix + key
+	// 같은 프로세스의 호출끼리도 서로 기다리도록 호출마다 다른 LockID 사용
+	lockID := newProcessLockID()
+
+	results, err := storeOf[TaskResultStore](s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		// 1. 저장된 값이 있으면 반환
+		getResult, err := results.GetTaskResult(ctx, GetTaskResultParams{Key: name})
+		if err != nil {
+			return nil, err
+		}
+		if getResult.Found {
+			return getResult.Result.Value, nil
+		}
+
+		// 2. 계산할 프로세스 선출
+		lockResult, err := s.client.TryXLock(ctx, TryXLockParams{
+			Name:       name,
+			LockID:     lockID,
+			TTLSeconds: s.options.LockTTLSeconds,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if lockResult.Acquired {
+			return s.compute(ctx, results, name, lockID, lockResult.ExpiresAt, fn)
+		}
+
+		// 다른 프로세스가 계산 중이면 대기 후 재확인
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.options.PollInterval):
+		}
+	}
+}
+
+// compute runs fn while holding the lock of the key and stores its value.
+// Losing the lock while fn runs only means another process may compute the value too, so fn is not interrupted.
+func (s *Singleflight) compute(
+	ctx context.Context,
+	results TaskResultStore,
+	name string,
+	lockID string,
+	expiresAt time.Time,
+	fn func(ctx context.Context) ([]byte, error),
+) ([]byte, error) {
+	defer func() {
+		_, _ = s.client.Unlock(context.WithoutCancel(ctx), UnlockParams{Name: name, LockID: lockID})
+	}()
+
+	// 확인과 잠금 사이에 다른 프로세스가 저장했을 수 있으므로 재확인
+	getResult, err := results.GetTaskResult(ctx, GetTaskResultParams{Key: name})
+	if err != nil {
+		return nil, err
+	}
+	if getResult.Found {
+		return getResult.Result.Value, nil
+	}
+
+	// 계산하는 동안 다른 프로세스가 기다리도록 잠금 유지
+	renewCtx, stopRenewal := context.WithCancel(ctx)
+	defer stopRenewal()
+	KeepAlive(renewCtx, s.client, KeepAliveParams{
+		Name:       name,
+		LockID:     lockID,
+		TTLSeconds: s.options.LockTTLSeconds,
+		ExpiresAt:  expiresAt,
+	})
+
+	value, err := fn(ctx)
+	stopRenewal()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := results.StoreTaskResult(ctx, StoreTaskResultParams{
+		Key:        name,
+		Value:      value,
+		TTLSeconds: s.options.ResultTTLSeconds,
+	}); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,98 @@
+package pglock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSingleflight_SharesResult tests that concurrent Singleflight calls compute once and that results expire and failures are not stored
+func TestSingleflight_SharesResult(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	results := client.(TaskResultStore)
+	key := testLockName(t)
+
+	// 1. 만료된 결과는 조회되지 않고 새 결과로 덮어쓸 수 있음
+	storeResult, err := results.StoreTaskResult(ctx, StoreTaskResultParams{Key: key + "/stored", Value: []byte("old"), TTLSeconds: 1})
+	require.NoError(t, err)
+	assert.True(t, storeResult.Stored)
+	assert.False(t, storeResult.Result.ExpiresAt.IsZero())
+
+	_, err = results.StoreTaskResult(ctx, StoreTaskResultParams{Key: key + "/pruned", Value: []byte("old"), TTLSeconds: 1})
+	require.NoError(t, err)
+
+	backend.expire(1)
+
+	getResult, err := results.GetTaskResult(ctx, GetTaskResultParams{Key: key + "/stored"})
+	require.NoError(t, err)
+	assert.False(t, getResult.Found)
+
+	storeResult, err = results.StoreTaskResult(ctx, StoreTaskResultParams{Key: key + "/stored", Value: []byte("new"), TTLSeconds: 60})
+	require.NoError(t, err)
+	assert.True(t, storeResult.Stored)
+	assert.Equal(t, []byte("new"), storeResult.Result.Value)
+
+	// 만료된 결과는 정리 시 삭제되고, 살아 있는 결과는 유지
+	pruneResult, err := results.PruneTaskResults(ctx, PruneTaskResultsParams{})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, pruneResult.Deleted, int64(1))
+
+	deleteResult, err := results.DeleteTaskResult(ctx, DeleteTaskResultParams{Key: key + "/pruned"})
+	require.NoError(t, err)
+	assert.False(t, deleteResult.Deleted)
+
+	getResult, err = results.GetTaskResult(ctx, GetTaskResultParams{Key: key + "/stored"})
+	require.NoError(t, err)
+	assert.True(t, getResult.Found)
+
+	// 2. 동시 호출은 한 번만 계산하고 결과를 공유
+	group := NewSingleflight(client, SingleflightOptions{ResultTTLSeconds: 1, PollInterval: 10 * time.Millisecond})
+
+	var runs atomic.Int32
+	compute := func(ctx context.Context) ([]byte, error) {
+		n := runs.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return []byte(fmt.Sprintf("value_%d", n)), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := group.Do(ctx, key, compute)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value_1"), value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), runs.Load())
+
+	// 3. 결과가 만료되면 다시 계산
+	backend.expire(1)
+
+	value, err := group.Do(ctx, key, compute)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value_2"), value)
+
+	// 4. 실패는 저장되지 않음
+	failingKey := testLockName(t) + "/failing"
+	_, err = group.Do(ctx, failingKey, func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("backend unavailable")
+	})
+	assert.EqualError(t, err, "backend unavailable")
+
+	value, err = group.Do(ctx, failingKey, compute)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value_3"), value)
+}
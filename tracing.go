@@ -0,0 +1,68 @@
+package pglock
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the instrumentation name used for the spans created by the lock client
+const TracerName = "github.com/myyrakle/pglock"
+
+// Span attribute keys
+const (
+	AttributeLockName  = attribute.Key("pglock.lock.name")
+	AttributeLockID    = attribute.Key("pglock.lock.id")
+	AttributeLockMode  = attribute.Key("pglock.lock.mode")
+	AttributeAcquired  = attribute.Key("pglock.acquired")
+	AttributeReleased  = attribute.Key("pglock.released")
+	AttributeRefreshed = attribute.Key("pglock.refreshed")
+	AttributeAttempts  = attribute.Key("pglock.attempts")
+	AttributeWaitTime  = attribute.Key("pglock.wait_time_ms")
+)
+
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	return provider.Tracer(TracerName)
+}
+
+// startSpan starts a span for a lock operation as a child of the span in ctx.
+func (i *instrumentation) startSpan(ctx context.Context, spanName string, mode LockMode, name string, lockID string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		AttributeLockName.String(name),
+		AttributeLockID.String(lockID),
+	}
+	if mode != "" {
+		attrs = append(attrs, AttributeLockMode.String(string(mode)))
+	}
+
+	return i.tracer.Start(ctx, spanName, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// endWaitSpan ends the span of a blocking acquire call with its attempt count and wait time.
+func endWaitSpan(span trace.Span, attempts int, waited time.Duration, acquired bool, err error) {
+	span.SetAttributes(
+		AttributeAttempts.Int(attempts),
+		AttributeWaitTime.Int64(waited.Milliseconds()),
+		AttributeAcquired.Bool(acquired),
+	)
+
+	endSpan(span, err)
+}
@@ -0,0 +1,358 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"time"
+)
+
+// Transaction-scoped locks are transaction-level advisory locks on a "hold" key of the lock name,
+// taken in the caller's transaction so that PostgreSQL releases them at COMMIT or ROLLBACK.
+// When EnableTxLocks is set, every acquisition decision for a name (regular or transaction-scoped)
+// is made while holding a transaction-level advisory lock on a "gate" key, so the two kinds of
+// holders always see each other. A transaction-scoped lock takes its hold key only after the
+// decision, while the gate is still held, so that pending requests never count as holders.
+//
+// Both keys use the two-key form of the advisory lock functions: the first key is AdvisoryLockClassID
+// and the second a 32-bit FNV-1a hash of the lock table, the key kind and the lock name. Applications
+// that use advisory locks themselves must not use that first key. Two names whose hashes collide
+// share their keys: their decisions are serialized and their transaction-scoped holders conflict.
+
+// DefaultAdvisoryLockClassID is the default first key of the advisory locks taken by transaction-scoped locks ("pglk")
+const DefaultAdvisoryLockClassID int32 = 0x70676c6b
+
+// advisoryKey derives the second advisory lock key from the lock table, the key kind and the lock name.
+func (c *lockClient) advisoryKey(kind string, name string) int32 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(c.options.LockTableName))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(kind))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(name))
+
+	return int32(hash.Sum32())
+}
+
+// txLockHolders counts the transactions holding transaction-scoped locks on a name
+type txLockHolders struct {
+	exclusive int
+	shared    int
+}
+
+// lockGate serializes acquisition decisions for a name until tx ends, and returns the
+// transaction-scoped holders other than the backend excludePID (0 for none).
+// It does nothing unless EnableTxLocks is set, since there are no such holders then.
+func (c *lockClient) lockGate(ctx context.Context, tx *sql.Tx, name string, excludePID int) (txLockHolders, error) {
+	if !c.options.EnableTxLocks {
+		return txLockHolders{}, nil
+	}
+
+	classID := c.options.AdvisoryLockClassID
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, $2);`, classID, c.advisoryKey("gate", name)); err != nil {
+		return txLockHolders{}, err
+	}
+
+	// 두 개의 int4 키는 pg_locks에서 classid, objid, objsubid = 2로 표시됨
+	selectQuery := `
+		SELECT
+			COUNT(*) FILTER (WHERE mode = 'ExclusiveLock'),
+			COUNT(*) FILTER (WHERE mode = 'ShareLock')
+		FROM pg_locks
+		WHERE locktype = 'advisory'
+			AND granted
+			AND database = (SELECT oid FROM pg_database WHERE datname = current_database())
+			AND classid = $1::int4::oid
+			AND objid = $2::int4::oid
+			AND objsubid = 2
+			AND pid <> $3;
+	`
+
+	var holders txLockHolders
+	err := tx.QueryRowContext(ctx, selectQuery, classID, c.advisoryKey("hold", name), excludePID).Scan(
+		&holders.exclusive, &holders.shared,
+	)
+	if err != nil {
+		return txLockHolders{}, err
+	}
+
+	return holders, nil
+}
+
+type TryXLockTxParams struct {
+	Name     string          // Lock Name: unique identifier for the lock
+	LockID   string          // Lock LockID: identifier for the entity requesting the lock, recorded in logs and audit events
+	Metadata json.RawMessage // [optional] JSON recorded with the audit event of this acquisition
+}
+
+type TryXLockTxResult struct {
+	Acquired bool // Whether the lock was acquired; it is held until the transaction ends
+}
+
+type XLockTxParams struct {
+	Name             string          // Lock Name: unique identifier for the lock
+	LockID           string          // Lock LockID: identifier for the entity requesting the lock, recorded in logs and audit events
+	IntervalDuration time.Duration   // Retry interval duration (default value: 100ms)
+	Metadata         json.RawMessage // [optional] JSON recorded with the audit event of this acquisition
+}
+
+type XLockTxResult struct{}
+
+type TrySLockTxParams struct {
+	Name           string          // Lock Name: unique identifier for the lock
+	LockID         string          // Lock LockID: identifier for the entity requesting the lock, recorded in logs and audit events
+	MaxSharedLocks int             // Maximum number of permits allowed (-1 or 0 for unlimited); a transaction-scoped shared lock takes one permit
+	Metadata       json.RawMessage // [optional] JSON recorded with the audit event of this acquisition
+}
+
+type TrySLockTxResult struct {
+	Acquired bool // Whether the lock was acquired; it is held until the transaction ends
+}
+
+// TryXLockTx attempts to acquire an exclusive lock that is held until tx commits or rolls back.
+// It conflicts with regular and transaction-scoped locks of the same name, and needs no Unlock.
+// Transaction-scoped locks require EnableTxLocks; otherwise ErrTxLocksDisabled is returned.
+func (c *lockClient) TryXLockTx(ctx context.Context, tx *sql.Tx, params TryXLockTxParams) (TryXLockTxResult, error) {
+	startedAt := time.Now()
+
+	acquired, err := c.tracedTryLockTx(ctx, tx, LockModeExclusive, params.Name, params.LockID, -1, params.Metadata)
+
+	c.observe(LockEvent{
+		Operation: OperationTryXLockTx,
+		Name:      params.Name,
+		Mode:      LockModeExclusive,
+		Outcome:   outcomeOf(err, acquired, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return TryXLockTxResult{Acquired: acquired}, err
+}
+
+// XLockTx continuously attempts to acquire an exclusive lock held until tx ends.
+func (c *lockClient) XLockTx(ctx context.Context, tx *sql.Tx, params XLockTxParams) (XLockTxResult, error) {
+	if params.IntervalDuration <= 0 {
+		params.IntervalDuration = DefaultRetryInterval
+	}
+
+	startedAt := time.Now()
+	ctx, span := c.startSpan(ctx, "pglock.XLockTx", LockModeExclusive, params.Name, params.LockID)
+
+	attempts := 0
+	var err error
+	for {
+		attempts++
+		c.logAttempt(ctx, LockModeExclusive, params.Name, params.LockID, attempts)
+
+		var acquired bool
+		acquired, err = c.tracedTryLockTx(ctx, tx, LockModeExclusive, params.Name, params.LockID, -1, params.Metadata)
+		if err != nil || acquired {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(params.IntervalDuration):
+			continue
+		}
+		break
+	}
+
+	endWaitSpan(span, attempts, time.Since(startedAt), err == nil, err)
+	c.observe(LockEvent{
+		Operation: OperationXLockTx,
+		Name:      params.Name,
+		Mode:      LockModeExclusive,
+		Outcome:   outcomeOf(err, true, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  attempts,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return XLockTxResult{}, err
+}
+
+// TrySLockTx attempts to acquire a shared lock that is held until tx commits or rolls back.
+// The lock takes one permit of MaxSharedLocks (or of the lock policy, if any).
+func (c *lockClient) TrySLockTx(ctx context.Context, tx *sql.Tx, params TrySLockTxParams) (TrySLockTxResult, error) {
+	// MaxSharedLocks가 0이면 제한 없음(-1)으로 취급
+	if params.MaxSharedLocks == 0 {
+		params.MaxSharedLocks = -1
+	}
+
+	startedAt := time.Now()
+
+	acquired, err := c.tracedTryLockTx(ctx, tx, LockModeShared, params.Name, params.LockID, params.MaxSharedLocks, params.Metadata)
+
+	c.observe(LockEvent{
+		Operation: OperationTrySLockTx,
+		Name:      params.Name,
+		Mode:      LockModeShared,
+		Outcome:   outcomeOf(err, acquired, OutcomeAcquired, OutcomeNotAcquired),
+		Attempts:  1,
+		Duration:  time.Since(startedAt),
+		Err:       err,
+	})
+
+	return TrySLockTxResult{Acquired: acquired}, err
+}
+
+func (c *lockClient) tracedTryLockTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	mode LockMode,
+	name string,
+	lockID string,
+	maxSharedLocks int,
+	metadata json.RawMessage,
+) (bool, error) {
+	spanName := "pglock.TryXLockTx"
+	if mode == LockModeShared {
+		spanName = "pglock.TrySLockTx"
+	}
+	ctx, span := c.startSpan(ctx, spanName, mode, name, lockID)
+
+	acquired, err := c.tryLockTx(ctx, tx, mode, name, lockID, maxSharedLocks, metadata)
+
+	span.SetAttributes(AttributeAcquired.Bool(acquired))
+	endSpan(span, err)
+
+	return acquired, err
+}
+
+func (c *lockClient) tryLockTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	mode LockMode,
+	name string,
+	lockID string,
+	maxSharedLocks int,
+	metadata json.RawMessage,
+) (bool, error) {
+	if !c.options.EnableTxLocks {
+		return false, ErrTxLocksDisabled
+	}
+
+	// 1. 호출자 트랜잭션의 backend 확인 (이미 보유한 hold 키는 충돌로 세지 않음)
+	var pid int
+	if err := tx.QueryRowContext(ctx, `SELECT pg_backend_pid();`).Scan(&pid); err != nil {
+		return false, err
+	}
+
+	// 2. 별도 트랜잭션에서 gate를 잡고 판단한 뒤, 허용되면 호출자 트랜잭션에서 hold 키 잠금
+	acquired, err := c.checkTxLock(ctx, tx, mode, name, lockID, maxSharedLocks, pid)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	// 3. 감사 기록도 호출자 트랜잭션과 함께 커밋
+	err = c.recordAuditEvents(ctx, tx, LockAuditEvent{
+		Name:       name,
+		LockID:     lockID,
+		Mode:       mode,
+		Event:      AuditEventAcquire,
+		OccurredAt: time.Now(),
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	c.logTxAcquired(ctx, mode, name, lockID)
+
+	return true, nil
+}
+
+// checkTxLock decides, under the gate, whether the transaction of backend pid may take the lock, and if so
+// takes its hold key in tx before the gate is released, so that the next decision counts it as a holder.
+func (c *lockClient) checkTxLock(
+	ctx context.Context,
+	tx *sql.Tx,
+	mode LockMode,
+	name string,
+	lockID string,
+	maxSharedLocks int,
+	pid int,
+) (bool, error) {
+	transaction, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer transaction.Rollback()
+
+	txHolders, err := c.lockGate(ctx, transaction, name, pid)
+	if err != nil {
+		return false, err
+	}
+
+	policy, _, err := c.findLockPolicy(ctx, transaction, name)
+	if err != nil {
+		return false, err
+	}
+
+	// gate를 잡은 뒤에는 진행 중인 일반 판단이 모두 커밋되어 있으므로 잠금 없이 조회
+	selectQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE name = $1;
+	`, lockRowColumns, c.options.LockTableName)
+
+	row, err := scanLockRow(transaction.QueryRowContext(ctx, selectQuery, name))
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if err == nil {
+		maxSharedLocks = row.maxSharedLocks
+	}
+	maxSharedLocks = policy.resolveMaxSharedLocks(maxSharedLocks)
+
+	lock, err := c.liveLockInfo(ctx, transaction, name, row, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	if lock.XLockID != "" || txHolders.exclusive > 0 {
+		c.logConflict(ctx, mode, name, lockID, conflictExclusiveHolder)
+		return false, nil
+	}
+
+	if mode == LockModeExclusive {
+		if len(lock.SharedLocks) > 0 || txHolders.shared > 0 {
+			c.logConflict(ctx, mode, name, lockID, conflictSharedHolders)
+			return false, nil
+		}
+	} else {
+		usedWeight := totalSharedWeight(lock.SharedLocks) + txHolders.shared
+		if maxSharedLocks != -1 && usedWeight+1 > maxSharedLocks {
+			c.logConflict(ctx, mode, name, lockID, conflictCapacity,
+				slog.Int("used_weight", usedWeight), slog.Int("max_shared_locks", maxSharedLocks))
+			return false, nil
+		}
+	}
+
+	// gate를 잡은 채로 hold 키 잠금 - 판단이 끝난 보유자만 다른 판단에서 보유자로 세어짐
+	tryLockFunction := "pg_try_advisory_xact_lock"
+	if mode == LockModeShared {
+		tryLockFunction = "pg_try_advisory_xact_lock_shared"
+	}
+
+	var held bool
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s($1, $2);`, tryLockFunction),
+		c.options.AdvisoryLockClassID, c.advisoryKey("hold", name),
+	).Scan(&held)
+	if err != nil {
+		return false, err
+	}
+	if !held {
+		// gate 밖에서 같은 키를 잡은 세션 (키 충돌)
+		c.logConflict(ctx, mode, name, lockID, conflictExclusiveHolder, slog.String("holder", "advisory"))
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,116 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Every lock row can carry a value (e.g. a cursor or checkpoint) guarded by the exclusive lock.
+// The value outlives its holders: it is kept when the lock is released or expires, so the next
+// holder continues from it. Only the live XLock holder can read or write it, and the ownership
+// check runs in the same transaction as the read or write.
+
+type GetValueParams struct {
+	Name   string // Lock Name: unique identifier for the lock
+	LockID string // Lock LockID: must hold the XLock of the lock
+}
+
+type GetValueResult struct {
+	Value []byte // Value of the lock (nil if none has been set)
+	Found bool   // Whether a value has been set
+}
+
+type SetValueParams struct {
+	Name   string // Lock Name: unique identifier for the lock
+	LockID string // Lock LockID: must hold the XLock of the lock
+	Value  []byte // New value of the lock; nil clears it
+}
+
+type SetValueResult struct{}
+
+// GetValue returns the value of a lock. It fails with ErrNotLockHolder unless LockID holds the XLock.
+func (c *lockClient) GetValue(ctx context.Context, params GetValueParams) (GetValueResult, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return GetValueResult{}, err
+	}
+	defer tx.Rollback()
+
+	// 보유자 확인과 조회를 같은 행 잠금 안에서 수행
+	value, err := c.selectHeldValue(ctx, tx, params.Name, params.LockID, "FOR SHARE")
+	if err != nil {
+		return GetValueResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return GetValueResult{}, err
+	}
+
+	return GetValueResult{Value: value, Found: value != nil}, nil
+}
+
+// SetValue replaces the value of a lock. It fails with ErrNotLockHolder unless LockID holds the XLock.
+func (c *lockClient) SetValue(ctx context.Context, params SetValueParams) (SetValueResult, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return SetValueResult{}, err
+	}
+	defer tx.Rollback()
+
+	tableName := c.options.LockTableName
+
+	// 1. 행 잠금 후 보유자 확인 - 커밋 전까지 다른 보유자로 넘어갈 수 없음
+	if _, err := c.selectHeldValue(ctx, tx, params.Name, params.LockID, "FOR UPDATE"); err != nil {
+		return SetValueResult{}, err
+	}
+
+	// 2. 값 갱신
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s
+		SET value = $1
+		WHERE name = $2;
+	`, tableName)
+	if _, err := tx.ExecContext(ctx, updateQuery, params.Value, params.Name); err != nil {
+		return SetValueResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SetValueResult{}, err
+	}
+
+	return SetValueResult{}, nil
+}
+
+// selectHeldValue locks the row of a lock with the given locking clause and returns its value,
+// or ErrNotLockHolder if lockID is not the live XLock holder.
+func (c *lockClient) selectHeldValue(ctx context.Context, tx *sql.Tx, name string, lockID string, lockingClause string) ([]byte, error) {
+	selectQuery := fmt.Sprintf(`
+		SELECT value, %s
+		FROM %s
+		WHERE name = $1
+		%s;
+	`, lockRowColumns, c.options.LockTableName, lockingClause)
+
+	var value []byte
+	row, err := scanLockRow(tx.QueryRowContext(ctx, selectQuery, name), &value)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotLockHolder
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 종료된 세션에 묶인 보유자는 보유하지 않은 것으로 취급
+	now := time.Now()
+	if err := c.expireDeadSessionHolders(ctx, tx, &row, now); err != nil {
+		return nil, err
+	}
+
+	if !row.heldExclusivelyBy(lockID, now) {
+		return nil, ErrNotLockHolder
+	}
+
+	return value, nil
+}
@@ -0,0 +1,66 @@
+package pglock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValue_OnlyLiveXLockHolder tests that only the live XLock holder can read and write the value of a lock, and that the value outlives its holders
+func TestValue_OnlyLiveXLockHolder(t *testing.T) {
+	backend := newTestBackend(t)
+	ctx := context.Background()
+	client := backend.Client
+	name := testLockName(t)
+
+	// 1. 보유자가 아니면 읽기/쓰기 모두 실패
+	_, err := client.GetValue(ctx, GetValueParams{Name: name, LockID: "worker_1"})
+	assert.ErrorIs(t, err, ErrNotLockHolder)
+
+	require.True(t, trySLock(t, client, name, "worker_1", -1, 1))
+	_, err = client.SetValue(ctx, SetValueParams{Name: name, LockID: "worker_1", Value: []byte("cursor-1")})
+	assert.ErrorIs(t, err, ErrNotLockHolder)
+	require.True(t, unlock(t, client, name, "worker_1"))
+
+	// 2. XLock 보유자는 읽고 쓸 수 있음
+	require.True(t, tryXLock(t, client, name, "worker_1", 1))
+
+	getResult, err := client.GetValue(ctx, GetValueParams{Name: name, LockID: "worker_1"})
+	require.NoError(t, err)
+	assert.False(t, getResult.Found)
+	assert.Nil(t, getResult.Value)
+
+	_, err = client.SetValue(ctx, SetValueParams{Name: name, LockID: "worker_1", Value: []byte("cursor-1")})
+	require.NoError(t, err)
+
+	_, err = client.SetValue(ctx, SetValueParams{Name: name, LockID: "worker_2", Value: []byte("cursor-x")})
+	assert.ErrorIs(t, err, ErrNotLockHolder)
+
+	getResult, err = client.GetValue(ctx, GetValueParams{Name: name, LockID: "worker_1"})
+	require.NoError(t, err)
+	assert.True(t, getResult.Found)
+	assert.Equal(t, []byte("cursor-1"), getResult.Value)
+
+	// 3. 만료된 보유자는 더 이상 쓸 수 없고, 다음 보유자가 값을 이어받음
+	backend.expire(1)
+
+	_, err = client.SetValue(ctx, SetValueParams{Name: name, LockID: "worker_1", Value: []byte("cursor-stale")})
+	assert.ErrorIs(t, err, ErrNotLockHolder)
+
+	require.True(t, tryXLock(t, client, name, "worker_2", 60))
+	getResult, err = client.GetValue(ctx, GetValueParams{Name: name, LockID: "worker_2"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cursor-1"), getResult.Value)
+
+	// 4. nil로 설정하면 값이 지워짐
+	_, err = client.SetValue(ctx, SetValueParams{Name: name, LockID: "worker_2", Value: nil})
+	require.NoError(t, err)
+	require.True(t, unlock(t, client, name, "worker_2"))
+	require.True(t, tryXLock(t, client, name, "worker_1", 60))
+
+	getResult, err = client.GetValue(ctx, GetValueParams{Name: name, LockID: "worker_1"})
+	require.NoError(t, err)
+	assert.False(t, getResult.Found)
+}